@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"CleanArch/configs"
+	"CleanArch/internal/event"
+	"CleanArch/internal/infra/consumer"
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/infra/messaging"
+	"CleanArch/internal/infra/messaging/rabbitmq"
+	"CleanArch/internal/infra/telemetry"
+
+	// database/sql drivers for every dialect.Dialect
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// ordersconsumer subscribes to order events and keeps the order_summaries
+// projection up to date. It is a template for further consumers: register
+// another consumer.Handler for the events it needs.
+func main() {
+	configs, err := configs.LoadConfig(".")
+	if err != nil {
+		panic(err)
+	}
+	// only the RabbitMQ adapter can consume; on another broker the queue
+	// would never receive the events and the projection would go stale
+	if broker := configs.MessageBroker; broker != "" && broker != messaging.BrokerRabbitMQ {
+		panic(fmt.Errorf("ordersconsumer: MESSAGE_BROKER %q is not supported, only %q", broker, messaging.BrokerRabbitMQ))
+	}
+
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), configs.TelemetryConfig("ordersconsumer"))
+	if err != nil {
+		panic(err)
+	}
+	defer shutdownTelemetry(context.Background())
+
+	dbDialect, err := dialect.Parse(configs.DBDriver)
+	if err != nil {
+		panic(err)
+	}
+	db, err := telemetry.OpenDB(dbDialect, dbDialect.DSN(configs.DSNConfig()))
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	processor := consumer.NewProcessor(db, dbDialect)
+	processor.MaxAttempts = configs.ConsumerMaxAttempts
+	projection := consumer.NewOrderSummaryProjection(dbDialect)
+	for _, name := range event.OrderEventNames {
+		processor.Register(name, projection)
+	}
+
+	topology := rabbitmq.DefaultTopology()
+	topology.Exchange = configs.RabbitMQExchange
+	topology.DeadLetterExchange = configs.RabbitMQExchange + ".dlx"
+	topology.Queues = []rabbitmq.Queue{{
+		Name:        configs.ConsumerQueue,
+		BindingKeys: []string{"order.#"},
+		RetryDelay:  configs.ConsumerRetryDelay,
+	}}
+	orderConsumer := rabbitmq.NewConsumer(rabbitmq.ConsumerConfig{
+		URL:         configs.AMQPURL(),
+		Topology:    topology,
+		Queue:       configs.ConsumerQueue,
+		Concurrency: configs.ConsumerConcurrency,
+		MaxBackoff:  configs.RabbitMQMaxBackoff,
+		// messages being processed at shutdown get as long as the servers do
+		DrainTimeout: configs.ShutdownTimeout,
+	}, processor)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println("Consuming", configs.ConsumerQueue)
+	orderConsumer.Run(ctx)
+}
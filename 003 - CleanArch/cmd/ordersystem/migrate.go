@@ -0,0 +1,58 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/infra/database/migrations"
+)
+
+const migrateUsage = "usage: ordersystem migrate [up | down [steps] | version]"
+
+// runMigrate implements the migrate subcommand.
+func runMigrate(db *sql.DB, d dialect.Dialect, args []string) error {
+	migrator, err := migrations.NewMigrator(db, d)
+	if err != nil {
+		return err
+	}
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		applied, err := migrator.Up()
+		printMigrations("applied", applied)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q\n%s", args[1], migrateUsage)
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		printMigrations("reverted", reverted)
+		return err
+	case "version":
+		version, err := migrator.Version()
+		if err != nil {
+			return err
+		}
+		fmt.Println("schema version", version)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", command, migrateUsage)
+	}
+}
+
+func printMigrations(action string, list []migrations.Migration) {
+	if len(list) == 0 {
+		fmt.Println("no migrations", action)
+	}
+	for _, migration := range list {
+		fmt.Printf("%s %04d_%s\n", action, migration.Version, migration.Name)
+	}
+}
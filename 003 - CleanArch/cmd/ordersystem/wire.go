@@ -9,6 +9,7 @@ import (
 	"CleanArch/internal/entity"
 	"CleanArch/internal/event"
 	"CleanArch/internal/infra/database"
+	"CleanArch/internal/infra/database/dialect"
 	"CleanArch/internal/infra/web"
 	"CleanArch/internal/usecase"
 	"CleanArch/pkg/events"
@@ -21,35 +22,129 @@ var setOrderRepositoryDependency = wire.NewSet(
 	wire.Bind(new(entity.OrderRepositoryInterface), new(*database.OrderRepository)),
 )
 
+var setOutboxRepositoryDependency = wire.NewSet(
+	database.NewOutboxRepository,
+	wire.Bind(new(entity.OutboxRepositoryInterface), new(*database.OutboxRepository)),
+)
+
+var setIdempotencyRepositoryDependency = wire.NewSet(
+	database.NewIdempotencyRepository,
+	wire.Bind(new(entity.IdempotencyRepositoryInterface), new(*database.IdempotencyRepository)),
+)
+
 var setEventDispatcherDependency = wire.NewSet(
 	events.NewEventDispatcher,
-	event.NewOrderCreated,
-	wire.Bind(new(events.EventInterface), new(*event.OrderCreated)),
 	wire.Bind(new(events.EventDispatcherInterface), new(*events.EventDispatcher)),
 )
 
-var setOrderCreatedEvent = wire.NewSet(
-	event.NewOrderCreated,
-	wire.Bind(new(events.EventInterface), new(*event.OrderCreated)),
-)
+var setOrderCreatedEvent = wire.NewSet(event.NewOrderCreatedFactory)
+
+var setOrderPaidEvent = wire.NewSet(event.NewOrderPaidFactory)
+
+var setOrderShippedEvent = wire.NewSet(event.NewOrderShippedFactory)
 
-func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
+var setOrderDeliveredEvent = wire.NewSet(event.NewOrderDeliveredFactory)
+
+var setOrderCancelledEvent = wire.NewSet(event.NewOrderCancelledFactory)
+
+var setOrderRefundedEvent = wire.NewSet(event.NewOrderRefundedFactory)
+
+var setOrderUpdatedEvent = wire.NewSet(event.NewOrderUpdatedFactory)
+
+var setOrderDeletedEvent = wire.NewSet(event.NewOrderDeletedFactory)
+
+func NewCreateOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
 	wire.Build(
 		setOrderRepositoryDependency,
+		setIdempotencyRepositoryDependency,
 		setOrderCreatedEvent,
 		usecase.NewCreateOrderUseCase,
 	)
 	return &usecase.CreateOrderUseCase{}
 }
 
-func NewListOrdersUseCase(db *sql.DB) *usecase.ListOrdersUseCase {
+func NewListOrdersUseCase(db *sql.DB, dbDialect dialect.Dialect) *usecase.ListOrdersUseCase {
 	wire.Build(setOrderRepositoryDependency, usecase.NewListOrdersUseCase)
 	return &usecase.ListOrdersUseCase{}
 }
 
-func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
+func NewGetOrderUseCase(db *sql.DB, dbDialect dialect.Dialect) *usecase.GetOrderUseCase {
+	wire.Build(setOrderRepositoryDependency, usecase.NewGetOrderUseCase)
+	return &usecase.GetOrderUseCase{}
+}
+
+func NewPayOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.PayOrderUseCase {
+	wire.Build(
+		setOrderRepositoryDependency,
+		setOrderPaidEvent,
+		usecase.NewPayOrderUseCase,
+	)
+	return &usecase.PayOrderUseCase{}
+}
+
+func NewShipOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.ShipOrderUseCase {
+	wire.Build(
+		setOrderRepositoryDependency,
+		setOrderShippedEvent,
+		usecase.NewShipOrderUseCase,
+	)
+	return &usecase.ShipOrderUseCase{}
+}
+
+func NewDeliverOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.DeliverOrderUseCase {
+	wire.Build(
+		setOrderRepositoryDependency,
+		setOrderDeliveredEvent,
+		usecase.NewDeliverOrderUseCase,
+	)
+	return &usecase.DeliverOrderUseCase{}
+}
+
+func NewCancelOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.CancelOrderUseCase {
+	wire.Build(
+		setOrderRepositoryDependency,
+		setOrderCancelledEvent,
+		usecase.NewCancelOrderUseCase,
+	)
+	return &usecase.CancelOrderUseCase{}
+}
+
+func NewRefundOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.RefundOrderUseCase {
+	wire.Build(
+		setOrderRepositoryDependency,
+		setOrderRefundedEvent,
+		usecase.NewRefundOrderUseCase,
+	)
+	return &usecase.RefundOrderUseCase{}
+}
+
+func NewUpdateOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.UpdateOrderUseCase {
+	wire.Build(
+		setOrderRepositoryDependency,
+		setOrderUpdatedEvent,
+		usecase.NewUpdateOrderUseCase,
+	)
+	return &usecase.UpdateOrderUseCase{}
+}
+
+func NewDeleteOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.DeleteOrderUseCase {
+	wire.Build(
+		setOrderRepositoryDependency,
+		setOrderDeletedEvent,
+		usecase.NewDeleteOrderUseCase,
+	)
+	return &usecase.DeleteOrderUseCase{}
+}
+
+func NewGetOutboxStatusUseCase(db *sql.DB, dbDialect dialect.Dialect) *usecase.GetOutboxStatusUseCase {
+	wire.Build(setOutboxRepositoryDependency, usecase.NewGetOutboxStatusUseCase)
+	return &usecase.GetOutboxStatusUseCase{}
+}
+
+func NewWebOrderHandler(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
 	wire.Build(
 		setOrderRepositoryDependency,
+		setIdempotencyRepositoryDependency,
 		setOrderCreatedEvent,
 		web.NewWebOrderHandler,
 	)
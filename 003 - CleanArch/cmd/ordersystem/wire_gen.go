@@ -10,6 +10,7 @@ import (
 	"CleanArch/internal/entity"
 	"CleanArch/internal/event"
 	"CleanArch/internal/infra/database"
+	"CleanArch/internal/infra/database/dialect"
 	"CleanArch/internal/infra/web"
 	"CleanArch/internal/usecase"
 	"CleanArch/pkg/events"
@@ -19,27 +20,92 @@ import (
 
 import (
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 )
 
 // Injectors from wire.go:
 
-func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
-	orderRepository := database.NewOrderRepository(db)
-	orderCreated := event.NewOrderCreated()
-	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, orderCreated, eventDispatcher)
+func NewCreateOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	idempotencyRepository := database.NewIdempotencyRepository(db, dbDialect)
+	eventFactory := event.NewOrderCreatedFactory()
+	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, idempotencyRepository, eventFactory, eventDispatcher)
 	return createOrderUseCase
 }
 
-func NewListOrdersUseCase(db *sql.DB) *usecase.ListOrdersUseCase {
-	orderRepository := database.NewOrderRepository(db)
+func NewListOrdersUseCase(db *sql.DB, dbDialect dialect.Dialect) *usecase.ListOrdersUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
 	listOrdersUseCase := usecase.NewListOrdersUseCase(orderRepository)
 	return listOrdersUseCase
 }
 
-func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
-	orderRepository := database.NewOrderRepository(db)
-	orderCreated := event.NewOrderCreated()
-	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, orderCreated)
+func NewGetOrderUseCase(db *sql.DB, dbDialect dialect.Dialect) *usecase.GetOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	getOrderUseCase := usecase.NewGetOrderUseCase(orderRepository)
+	return getOrderUseCase
+}
+
+func NewPayOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.PayOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	eventFactory := event.NewOrderPaidFactory()
+	payOrderUseCase := usecase.NewPayOrderUseCase(orderRepository, eventFactory, eventDispatcher)
+	return payOrderUseCase
+}
+
+func NewShipOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.ShipOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	eventFactory := event.NewOrderShippedFactory()
+	shipOrderUseCase := usecase.NewShipOrderUseCase(orderRepository, eventFactory, eventDispatcher)
+	return shipOrderUseCase
+}
+
+func NewDeliverOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.DeliverOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	eventFactory := event.NewOrderDeliveredFactory()
+	deliverOrderUseCase := usecase.NewDeliverOrderUseCase(orderRepository, eventFactory, eventDispatcher)
+	return deliverOrderUseCase
+}
+
+func NewCancelOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.CancelOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	eventFactory := event.NewOrderCancelledFactory()
+	cancelOrderUseCase := usecase.NewCancelOrderUseCase(orderRepository, eventFactory, eventDispatcher)
+	return cancelOrderUseCase
+}
+
+func NewRefundOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.RefundOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	eventFactory := event.NewOrderRefundedFactory()
+	refundOrderUseCase := usecase.NewRefundOrderUseCase(orderRepository, eventFactory, eventDispatcher)
+	return refundOrderUseCase
+}
+
+func NewUpdateOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.UpdateOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	eventFactory := event.NewOrderUpdatedFactory()
+	updateOrderUseCase := usecase.NewUpdateOrderUseCase(orderRepository, eventFactory, eventDispatcher)
+	return updateOrderUseCase
+}
+
+func NewDeleteOrderUseCase(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *usecase.DeleteOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	eventFactory := event.NewOrderDeletedFactory()
+	deleteOrderUseCase := usecase.NewDeleteOrderUseCase(orderRepository, eventFactory, eventDispatcher)
+	return deleteOrderUseCase
+}
+
+func NewGetOutboxStatusUseCase(db *sql.DB, dbDialect dialect.Dialect) *usecase.GetOutboxStatusUseCase {
+	outboxRepository := database.NewOutboxRepository(db, dbDialect)
+	getOutboxStatusUseCase := usecase.NewGetOutboxStatusUseCase(outboxRepository)
+	return getOutboxStatusUseCase
+}
+
+func NewWebOrderHandler(db *sql.DB, dbDialect dialect.Dialect, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
+	orderRepository := database.NewOrderRepository(db, dbDialect)
+	idempotencyRepository := database.NewIdempotencyRepository(db, dbDialect)
+	eventFactory := event.NewOrderCreatedFactory()
+	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, idempotencyRepository, eventFactory)
 	return webOrderHandler
 }
 
@@ -47,6 +113,24 @@ func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterf
 
 var setOrderRepositoryDependency = wire.NewSet(database.NewOrderRepository, wire.Bind(new(entity.OrderRepositoryInterface), new(*database.OrderRepository)))
 
-var setEventDispatcherDependency = wire.NewSet(events.NewEventDispatcher, event.NewOrderCreated, wire.Bind(new(events.EventInterface), new(*event.OrderCreated)), wire.Bind(new(events.EventDispatcherInterface), new(*events.EventDispatcher)))
+var setOutboxRepositoryDependency = wire.NewSet(database.NewOutboxRepository, wire.Bind(new(entity.OutboxRepositoryInterface), new(*database.OutboxRepository)))
+
+var setIdempotencyRepositoryDependency = wire.NewSet(database.NewIdempotencyRepository, wire.Bind(new(entity.IdempotencyRepositoryInterface), new(*database.IdempotencyRepository)))
+
+var setEventDispatcherDependency = wire.NewSet(events.NewEventDispatcher, wire.Bind(new(events.EventDispatcherInterface), new(*events.EventDispatcher)))
+
+var setOrderCreatedEvent = wire.NewSet(event.NewOrderCreatedFactory)
+
+var setOrderPaidEvent = wire.NewSet(event.NewOrderPaidFactory)
+
+var setOrderShippedEvent = wire.NewSet(event.NewOrderShippedFactory)
+
+var setOrderDeliveredEvent = wire.NewSet(event.NewOrderDeliveredFactory)
+
+var setOrderCancelledEvent = wire.NewSet(event.NewOrderCancelledFactory)
+
+var setOrderRefundedEvent = wire.NewSet(event.NewOrderRefundedFactory)
+
+var setOrderUpdatedEvent = wire.NewSet(event.NewOrderUpdatedFactory)
 
-var setOrderCreatedEvent = wire.NewSet(event.NewOrderCreated, wire.Bind(new(events.EventInterface), new(*event.OrderCreated)))
+var setOrderDeletedEvent = wire.NewSet(event.NewOrderDeletedFactory)
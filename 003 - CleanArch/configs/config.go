@@ -1,17 +1,95 @@
 package configs
 
-import "github.com/spf13/viper"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"CleanArch/internal/infra/auth"
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/infra/graph"
+	"CleanArch/internal/infra/telemetry"
+	"CleanArch/internal/infra/web/webserver"
+
+	"github.com/spf13/viper"
+)
 
 type conf struct {
-	DBDriver          string `mapstructure:"DB_DRIVER"`
-	DBHost            string `mapstructure:"DB_HOST"`
-	DBPort            string `mapstructure:"DB_PORT"`
-	DBUser            string `mapstructure:"DB_USER"`
-	DBPassword        string `mapstructure:"DB_PASSWORD"`
-	DBName            string `mapstructure:"DB_NAME"`
-	WebServerPort     string `mapstructure:"WEB_SERVER_PORT"`
-	GRPCServerPort    string `mapstructure:"GRPC_SERVER_PORT"`
-	GraphQLServerPort string `mapstructure:"GRAPHQL_SERVER_PORT"`
+	DBDriver            string        `mapstructure:"DB_DRIVER"`
+	DBHost              string        `mapstructure:"DB_HOST"`
+	DBPort              string        `mapstructure:"DB_PORT"`
+	DBUser              string        `mapstructure:"DB_USER"`
+	DBPassword          string        `mapstructure:"DB_PASSWORD"`
+	DBName              string        `mapstructure:"DB_NAME"`
+	DBSSLMode           string        `mapstructure:"DB_SSL_MODE"`
+	DBAutoMigrate       bool          `mapstructure:"DB_AUTO_MIGRATE"`
+	WebServerPort       string        `mapstructure:"WEB_SERVER_PORT"`
+	GRPCServerPort      string        `mapstructure:"GRPC_SERVER_PORT"`
+	GraphQLServerPort   string        `mapstructure:"GRAPHQL_SERVER_PORT"`
+	ServerPort          string        `mapstructure:"SERVER_PORT"`
+	ShutdownTimeout     time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ShutdownDrainDelay  time.Duration `mapstructure:"SHUTDOWN_DRAIN_DELAY"`
+	HealthCheckTimeout  time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+	CORSAllowedOrigins  string        `mapstructure:"HTTP_CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods  string        `mapstructure:"HTTP_CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders  string        `mapstructure:"HTTP_CORS_ALLOWED_HEADERS"`
+	CORSExposedHeaders  string        `mapstructure:"HTTP_CORS_EXPOSED_HEADERS"`
+	CORSCredentials     bool          `mapstructure:"HTTP_CORS_ALLOW_CREDENTIALS"`
+	CORSMaxAge          time.Duration `mapstructure:"HTTP_CORS_MAX_AGE"`
+	HTTPCompression     int           `mapstructure:"HTTP_COMPRESSION_LEVEL"`
+	HTTPMaxBodyBytes    int64         `mapstructure:"HTTP_MAX_BODY_BYTES"`
+	HTTPTrustProxy      bool          `mapstructure:"HTTP_TRUST_PROXY"`
+	HTTPRequestTimeout  time.Duration `mapstructure:"HTTP_REQUEST_TIMEOUT"`
+	HTTPRouteTimeouts   string        `mapstructure:"HTTP_ROUTE_TIMEOUTS"`
+	OutboxBatchSize     int           `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxPollInterval  time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	OutboxMaxBackoff    time.Duration `mapstructure:"OUTBOX_MAX_BACKOFF"`
+	OutboxMaxAttempts   int           `mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+	EventsAsyncWorkers  int           `mapstructure:"EVENTS_ASYNC_WORKERS"`
+	EventsQueueSize     int           `mapstructure:"EVENTS_QUEUE_SIZE"`
+	MessageBroker       string        `mapstructure:"MESSAGE_BROKER"`
+	KafkaBrokers        string        `mapstructure:"KAFKA_BROKERS"`
+	KafkaTopic          string        `mapstructure:"KAFKA_TOPIC"`
+	NATSURL             string        `mapstructure:"NATS_URL"`
+	NATSStream          string        `mapstructure:"NATS_STREAM"`
+	NATSSubjectPrefix   string        `mapstructure:"NATS_SUBJECT_PREFIX"`
+	ConsumerQueue       string        `mapstructure:"CONSUMER_QUEUE"`
+	ConsumerConcurrency int           `mapstructure:"CONSUMER_CONCURRENCY"`
+	ConsumerMaxAttempts int           `mapstructure:"CONSUMER_MAX_ATTEMPTS"`
+	ConsumerRetryDelay  time.Duration `mapstructure:"CONSUMER_RETRY_DELAY"`
+	RabbitMQURL         string        `mapstructure:"RABBITMQ_URL"`
+	RabbitMQHost        string        `mapstructure:"RABBITMQ_HOST"`
+	RabbitMQPort        string        `mapstructure:"RABBITMQ_PORT"`
+	RabbitMQUser        string        `mapstructure:"RABBITMQ_USER"`
+	RabbitMQPassword    string        `mapstructure:"RABBITMQ_PASSWORD"`
+	RabbitMQVHost       string        `mapstructure:"RABBITMQ_VHOST"`
+	RabbitMQExchange    string        `mapstructure:"RABBITMQ_EXCHANGE"`
+	RabbitMQMaxBackoff  time.Duration `mapstructure:"RABBITMQ_MAX_BACKOFF"`
+	IdempotencyTTL      time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+	IdempotencyPurge    time.Duration `mapstructure:"IDEMPOTENCY_PURGE_INTERVAL"`
+	AuthJWKSFile        string        `mapstructure:"AUTH_JWKS_FILE"`
+	AuthStaticKey       string        `mapstructure:"AUTH_STATIC_KEY"`
+	AuthIssuer          string        `mapstructure:"AUTH_ISSUER"`
+	AuthAudience        string        `mapstructure:"AUTH_AUDIENCE"`
+	AuthLeeway          time.Duration `mapstructure:"AUTH_LEEWAY"`
+	TelemetryExporter   string        `mapstructure:"TELEMETRY_EXPORTER"`
+	TelemetryService    string        `mapstructure:"TELEMETRY_SERVICE_NAME"`
+	TelemetryEndpoint   string        `mapstructure:"TELEMETRY_OTLP_ENDPOINT"`
+	TelemetryInsecure   bool          `mapstructure:"TELEMETRY_OTLP_INSECURE"`
+	TelemetrySampling   float64       `mapstructure:"TELEMETRY_SAMPLE_RATIO"`
+	TelemetryInterval   time.Duration `mapstructure:"TELEMETRY_METRIC_INTERVAL"`
+	AppEnv              string        `mapstructure:"APP_ENV"`
+	GraphQLComplexity   int           `mapstructure:"GRAPHQL_COMPLEXITY_LIMIT"`
+	GraphQLDepth        int           `mapstructure:"GRAPHQL_DEPTH_LIMIT"`
+	GraphQLAPQCacheSize int           `mapstructure:"GRAPHQL_APQ_CACHE_SIZE"`
+	OperationTimeouts   string        `mapstructure:"OPERATION_TIMEOUTS"`
+	DefaultTimeout      time.Duration `mapstructure:"OPERATION_TIMEOUT"`
+	operationTimeouts   map[string]time.Duration
+	routeTimeouts       map[string]time.Duration
 }
 
 func LoadConfig(path string) (*conf, error) {
@@ -21,6 +99,69 @@ func LoadConfig(path string) (*conf, error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
+	// Unmarshal only sees keys viper already knows, so every setting needs a
+	// default for an environment variable to override it.
+	viper.SetDefault("DB_DRIVER", "mysql")
+	viper.SetDefault("DB_SSL_MODE", "disable")
+	viper.SetDefault("DB_AUTO_MIGRATE", false)
+	viper.SetDefault("SERVER_PORT", "")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "30s")
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", "0s")
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("HTTP_CORS_ALLOWED_ORIGINS", "")
+	viper.SetDefault("HTTP_CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE")
+	viper.SetDefault("HTTP_CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,Idempotency-Key,If-Match,X-Request-Id")
+	viper.SetDefault("HTTP_CORS_EXPOSED_HEADERS", "Deprecation,ETag,X-Request-Id")
+	viper.SetDefault("HTTP_CORS_ALLOW_CREDENTIALS", false)
+	viper.SetDefault("HTTP_CORS_MAX_AGE", "10m")
+	viper.SetDefault("HTTP_COMPRESSION_LEVEL", 5)
+	viper.SetDefault("HTTP_MAX_BODY_BYTES", 1<<20)
+	viper.SetDefault("HTTP_TRUST_PROXY", false)
+	viper.SetDefault("HTTP_REQUEST_TIMEOUT", "30s")
+	viper.SetDefault("HTTP_ROUTE_TIMEOUTS", "")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", "5m")
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 20)
+	viper.SetDefault("EVENTS_QUEUE_SIZE", 256)
+	viper.SetDefault("EVENTS_ASYNC_WORKERS", 0)
+	viper.SetDefault("MESSAGE_BROKER", "rabbitmq")
+	viper.SetDefault("KAFKA_BROKERS", "kafka:9092")
+	viper.SetDefault("KAFKA_TOPIC", "orders")
+	viper.SetDefault("NATS_URL", "nats://nats:4222")
+	viper.SetDefault("NATS_STREAM", "ORDERS")
+	viper.SetDefault("NATS_SUBJECT_PREFIX", "orders")
+	viper.SetDefault("CONSUMER_QUEUE", "orders.events")
+	viper.SetDefault("CONSUMER_CONCURRENCY", 4)
+	viper.SetDefault("CONSUMER_MAX_ATTEMPTS", 5)
+	viper.SetDefault("CONSUMER_RETRY_DELAY", "10s")
+	viper.SetDefault("RABBITMQ_URL", "")
+	viper.SetDefault("RABBITMQ_HOST", "rabbitmq")
+	viper.SetDefault("RABBITMQ_PORT", "5672")
+	viper.SetDefault("RABBITMQ_USER", "guest")
+	viper.SetDefault("RABBITMQ_PASSWORD", "guest")
+	viper.SetDefault("RABBITMQ_VHOST", "/")
+	viper.SetDefault("RABBITMQ_EXCHANGE", "orders")
+	viper.SetDefault("RABBITMQ_MAX_BACKOFF", "30s")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("IDEMPOTENCY_PURGE_INTERVAL", "1h")
+	viper.SetDefault("AUTH_JWKS_FILE", "")
+	viper.SetDefault("AUTH_STATIC_KEY", "")
+	viper.SetDefault("AUTH_ISSUER", "")
+	viper.SetDefault("AUTH_AUDIENCE", "")
+	viper.SetDefault("AUTH_LEEWAY", "30s")
+	viper.SetDefault("TELEMETRY_SERVICE_NAME", "")
+	viper.SetDefault("TELEMETRY_EXPORTER", "none")
+	viper.SetDefault("TELEMETRY_OTLP_ENDPOINT", "localhost:4317")
+	viper.SetDefault("TELEMETRY_OTLP_INSECURE", true)
+	viper.SetDefault("TELEMETRY_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("TELEMETRY_METRIC_INTERVAL", "30s")
+	viper.SetDefault("OPERATION_TIMEOUT", "10s")
+	viper.SetDefault("OPERATION_TIMEOUTS", "")
+	viper.SetDefault("APP_ENV", "production")
+	viper.SetDefault("GRAPHQL_COMPLEXITY_LIMIT", 5000)
+	viper.SetDefault("GRAPHQL_DEPTH_LIMIT", 10)
+	viper.SetDefault("GRAPHQL_APQ_CACHE_SIZE", 1000)
 	err := viper.ReadInConfig()
 	if err != nil {
 		panic(err)
@@ -29,5 +170,171 @@ func LoadConfig(path string) (*conf, error) {
 	if err != nil {
 		panic(err)
 	}
+	cfg.operationTimeouts, err = parseTimeouts("OPERATION_TIMEOUTS", cfg.OperationTimeouts)
+	if err != nil {
+		panic(err)
+	}
+	cfg.routeTimeouts, err = parseTimeouts("HTTP_ROUTE_TIMEOUTS", cfg.HTTPRouteTimeouts)
+	if err != nil {
+		panic(err)
+	}
 	return cfg, err
 }
+
+// OperationTimeout returns the deadline for the named use case, such as
+// "CreateOrder": its OPERATION_TIMEOUTS entry when present, otherwise
+// OPERATION_TIMEOUT. Zero means no deadline.
+func (c *conf) OperationTimeout(operation string) time.Duration {
+	if timeout, ok := c.operationTimeouts[operation]; ok {
+		return timeout
+	}
+	return c.DefaultTimeout
+}
+
+// parseTimeouts reads a list like "CreateOrder=3s,ListOrders=2s" from the
+// setting name.
+func parseTimeouts(name, value string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		operation, duration, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s: %q: want name=duration", name, entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q: %w", name, entry, err)
+		}
+		timeouts[strings.TrimSpace(operation)] = timeout
+	}
+	return timeouts, nil
+}
+
+// DSNConfig returns the DB_* connection settings. With DB_DRIVER=sqlite3,
+// DB_NAME is the database file.
+func (c *conf) DSNConfig() dialect.DSNConfig {
+	return dialect.DSNConfig{
+		Host:     c.DBHost,
+		Port:     c.DBPort,
+		User:     c.DBUser,
+		Password: c.DBPassword,
+		Name:     c.DBName,
+		SSLMode:  c.DBSSLMode,
+	}
+}
+
+// AuthVerifier builds the bearer token verifier from AUTH_JWKS_FILE or, when
+// that is unset, AUTH_STATIC_KEY. It returns nil when neither is set, which
+// leaves the APIs open.
+func (c *conf) AuthVerifier() (*auth.Verifier, error) {
+	var keys *auth.KeySet
+	var err error
+	switch {
+	case c.AuthJWKSFile != "":
+		keys, err = auth.LoadJWKS(c.AuthJWKSFile)
+	case c.AuthStaticKey != "":
+		keys, err = auth.NewStaticKeySet(c.AuthStaticKey)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewVerifier(keys, auth.VerifierOptions{
+		Issuer:   c.AuthIssuer,
+		Audience: c.AuthAudience,
+		Leeway:   c.AuthLeeway,
+	}), nil
+}
+
+// Development reports whether APP_ENV is "development" or "dev", which
+// enables tools such as the GraphQL playground and schema introspection.
+func (c *conf) Development() bool {
+	return c.AppEnv == "development" || c.AppEnv == "dev"
+}
+
+// GraphQLConfig returns the limits of the GraphQL server; verifier is the one
+// from AuthVerifier.
+func (c *conf) GraphQLConfig(verifier *auth.Verifier) graph.ServerConfig {
+	return graph.ServerConfig{
+		Verifier:            verifier,
+		ComplexityLimit:     c.GraphQLComplexity,
+		DepthLimit:          c.GraphQLDepth,
+		PersistedQueryCache: c.GraphQLAPQCacheSize,
+		Introspection:       c.Development(),
+	}
+}
+
+// WebServerConfig is the middleware stack of the REST server. Allowing
+// credentials from any origin would let every site act as the signed-in
+// user, so that combination is rejected.
+func (c *conf) WebServerConfig() (webserver.Config, error) {
+	origins := splitList(c.CORSAllowedOrigins)
+	if c.CORSCredentials && slices.Contains(origins, "*") {
+		return webserver.Config{}, errors.New("HTTP_CORS_ALLOW_CREDENTIALS needs explicit HTTP_CORS_ALLOWED_ORIGINS, not *")
+	}
+	return webserver.Config{
+		CORS: webserver.CORSConfig{
+			AllowedOrigins:   origins,
+			AllowedMethods:   splitList(c.CORSAllowedMethods),
+			AllowedHeaders:   splitList(c.CORSAllowedHeaders),
+			ExposedHeaders:   splitList(c.CORSExposedHeaders),
+			AllowCredentials: c.CORSCredentials,
+			MaxAge:           c.CORSMaxAge,
+		},
+		CompressionLevel: c.HTTPCompression,
+		MaxBodyBytes:     c.HTTPMaxBodyBytes,
+		TrustProxy:       c.HTTPTrustProxy,
+		RequestTimeout:   c.HTTPRequestTimeout,
+		RouteTimeouts:    c.routeTimeouts,
+	}, nil
+}
+
+// splitList reads a comma-separated list, dropping empty entries.
+func splitList(value string) []string {
+	var list []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// TelemetryConfig returns the TELEMETRY_* settings. serviceName names the
+// binary when TELEMETRY_SERVICE_NAME is unset.
+func (c *conf) TelemetryConfig(serviceName string) telemetry.Config {
+	if c.TelemetryService != "" {
+		serviceName = c.TelemetryService
+	}
+	return telemetry.Config{
+		Exporter:       c.TelemetryExporter,
+		ServiceName:    serviceName,
+		OTLPEndpoint:   c.TelemetryEndpoint,
+		OTLPInsecure:   c.TelemetryInsecure,
+		SampleRatio:    c.TelemetrySampling,
+		MetricInterval: c.TelemetryInterval,
+	}
+}
+
+// AMQPURL returns RABBITMQ_URL when set, otherwise builds the URL from the
+// individual RABBITMQ_* settings.
+func (c *conf) AMQPURL() string {
+	if c.RabbitMQURL != "" {
+		return c.RabbitMQURL
+	}
+	u := url.URL{
+		Scheme: "amqp",
+		User:   url.UserPassword(c.RabbitMQUser, c.RabbitMQPassword),
+		Host:   net.JoinHostPort(c.RabbitMQHost, c.RabbitMQPort),
+		Path:   "/",
+	}
+	if c.RabbitMQVHost != "/" {
+		u.Path = "/" + c.RabbitMQVHost
+		u.RawPath = "/" + url.PathEscape(c.RabbitMQVHost)
+	}
+	return u.String()
+}
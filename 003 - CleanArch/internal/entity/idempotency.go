@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MaxIdempotencyKeyLength bounds client supplied keys to what the
+// idempotency_keys table stores.
+const MaxIdempotencyKeyLength = 255
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrIdempotencyKeyTaken means another request stored the key first.
+	ErrIdempotencyKeyTaken       = errors.New("idempotency key is already stored")
+	ErrIdempotencyRecordNotFound = errors.New("idempotency record not found")
+)
+
+// IdempotencyRecord remembers the outcome of a request sent with an
+// idempotency key. Response is nil while the request is still running.
+type IdempotencyRecord struct {
+	Operation   string
+	Key         string
+	Fingerprint string
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func NewIdempotencyRecord(operation, key, fingerprint string, ttl time.Duration) IdempotencyRecord {
+	now := time.Now().UTC()
+	return IdempotencyRecord{
+		Operation:   operation,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+// Replay returns the stored response for a repeated request with the given
+// fingerprint.
+func (r IdempotencyRecord) Replay(fingerprint string) ([]byte, error) {
+	if r.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if r.Response == nil {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return r.Response, nil
+}
+
+// IdempotencyRepositoryInterface reads the records that
+// OrderRepositoryInterface.SaveWithIdempotency writes along with an order.
+type IdempotencyRepositoryInterface interface {
+	// Find returns the unexpired record for operation and key, or
+	// ErrIdempotencyRecordNotFound.
+	Find(ctx context.Context, operation, key string) (IdempotencyRecord, error)
+	// DeleteExpired deletes the records that expired by now and returns how
+	// many there were.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -1,7 +1,32 @@
 package entity
 
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrOrderAlreadyExists = errors.New("order already exists")
+	ErrVersionConflict    = errors.New("order was modified by another request")
+)
+
 type OrderRepositoryInterface interface {
-	Save(order *Order) error
-	List() ([]Order, error)
-	GetTotal() (int, error)
+	Save(ctx context.Context, order *Order) error
+	// SaveWithOutbox saves order and messages in a single transaction. An
+	// existing order with the same ID yields ErrOrderAlreadyExists.
+	SaveWithOutbox(ctx context.Context, order *Order, messages ...OutboxMessage) error
+	// SaveWithIdempotency is SaveWithOutbox that also stores record,
+	// replacing an expired one with the same key. An unexpired one yields
+	// ErrIdempotencyKeyTaken and nothing is saved.
+	SaveWithIdempotency(ctx context.Context, order *Order, record IdempotencyRecord, messages ...OutboxMessage) error
+	// Update persists order and messages if its stored version still equals
+	// order.Version, then increments order.Version. A stale version yields
+	// ErrVersionConflict.
+	Update(ctx context.Context, order *Order, messages ...OutboxMessage) error
+	Delete(ctx context.Context, id string, version int64, messages ...OutboxMessage) error
+	FindByID(ctx context.Context, id string) (*Order, error)
+	// FindByIDs returns the orders found among ids, in no particular order.
+	FindByIDs(ctx context.Context, ids []string) ([]Order, error)
+	List(ctx context.Context, query OrderListQuery) ([]Order, error)
 }
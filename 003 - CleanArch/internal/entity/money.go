@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	ErrInvalidCurrency  = errors.New("invalid currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrAmountOverflow   = errors.New("amount is too large")
+)
+
+// minorUnitExponents lists ISO 4217 currencies whose minor unit is not cents.
+var minorUnitExponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"CLP": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+}
+
+// Money is an amount in the currency's minor units (cents for BRL or USD),
+// so arithmetic never goes through floating point.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) (Money, error) {
+	m := Money{Amount: amount, Currency: strings.ToUpper(currency)}
+	if !IsValidCurrency(m.Currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+	return m, nil
+}
+
+func IsValidCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Multiply returns ErrAmountOverflow instead of wrapping around when the
+// product does not fit in an int64.
+func (m Money) Multiply(quantity int64) (Money, error) {
+	product, ok := multiply(m.Amount, quantity)
+	if !ok {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// ApplyRate returns the share of m given by a rate in basis points
+// (1000 = 10%), rounding half away from zero to the nearest minor unit.
+func (m Money) ApplyRate(basisPoints int64) (Money, error) {
+	product, ok := multiply(m.Amount, basisPoints)
+	if !ok {
+		return Money{}, ErrAmountOverflow
+	}
+	amount := product / 10000
+	remainder := product % 10000
+	if remainder >= 5000 {
+		amount++
+	} else if remainder <= -5000 {
+		amount--
+	}
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// multiply reports whether a × b fits in an int64.
+func multiply(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) String() string {
+	exponent, ok := minorUnitExponents[m.Currency]
+	if !ok {
+		exponent = 2
+	}
+	if exponent == 0 {
+		return fmt.Sprintf("%s %d", m.Currency, m.Amount)
+	}
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int64(1)
+	for i := 0; i < exponent; i++ {
+		unit *= 10
+	}
+	return fmt.Sprintf("%s %s%d.%0*d", m.Currency, sign, amount/unit, exponent, amount%unit)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenALowercaseCurrency_WhenICallNewMoney_ThenShouldNormalizeIt(t *testing.T) {
+	m, err := NewMoney(100, "brl")
+	assert.Nil(t, err)
+	assert.Equal(t, Money{Amount: 100, Currency: "BRL"}, m)
+}
+
+func TestGivenAnInvalidCurrency_WhenICallNewMoney_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := NewMoney(100, "R$")
+	assert.Equal(t, ErrInvalidCurrency, err)
+}
+
+func TestGivenDifferentCurrencies_WhenIAdd_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := Money{Amount: 100, Currency: "BRL"}.Add(Money{Amount: 100, Currency: "USD"})
+	assert.Equal(t, ErrCurrencyMismatch, err)
+}
+
+func TestGivenARate_WhenIApplyIt_ThenShouldRoundHalfAwayFromZero(t *testing.T) {
+	for amount, want := range map[int64]int64{50: 5, 5: 1, 4: 0, -5: -1} {
+		share, err := Money{Amount: amount, Currency: "BRL"}.ApplyRate(1000)
+		assert.NoError(t, err)
+		assert.Equal(t, want, share.Amount, amount)
+	}
+}
+
+func TestGivenAmountsThatDoNotFit_WhenIComputeWithThem_ThenShouldReceiveAnOverflowError(t *testing.T) {
+	big := Money{Amount: math.MaxInt64 / 2, Currency: "BRL"}
+
+	_, err := big.Multiply(3)
+	assert.Equal(t, ErrAmountOverflow, err)
+	_, err = Money{Amount: math.MinInt64, Currency: "BRL"}.Multiply(-1)
+	assert.Equal(t, ErrAmountOverflow, err)
+	_, err = big.ApplyRate(10000)
+	assert.Equal(t, ErrAmountOverflow, err)
+	_, err = big.Add(big)
+	assert.NoError(t, err)
+	_, err = big.Add(Money{Amount: math.MaxInt64/2 + 2, Currency: "BRL"})
+	assert.Equal(t, ErrAmountOverflow, err)
+	_, err = Money{Amount: math.MinInt64, Currency: "BRL"}.Add(Money{Amount: -1, Currency: "BRL"})
+	assert.Equal(t, ErrAmountOverflow, err)
+
+	product, err := big.Multiply(2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64-1), product.Amount)
+}
+
+func TestGivenAnAmount_WhenIFormatIt_ThenShouldUseTheCurrencyMinorUnits(t *testing.T) {
+	assert.Equal(t, "BRL 10.05", Money{Amount: 1005, Currency: "BRL"}.String())
+	assert.Equal(t, "USD -0.50", Money{Amount: -50, Currency: "USD"}.String())
+	assert.Equal(t, "JPY 1005", Money{Amount: 1005, Currency: "JPY"}.String())
+	assert.Equal(t, "KWD 1.005", Money{Amount: 1005, Currency: "KWD"}.String())
+}
@@ -1,19 +1,43 @@
 package entity
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrOrderNotEditable = errors.New("only pending orders can be edited")
+	ErrNoItems          = errors.New("must have at least one item")
+)
 
 type Order struct {
-	ID         string
-	Price      float64
-	Tax        float64
-	FinalPrice float64
+	ID          string
+	Currency    string
+	Items       []OrderItem
+	Price       Money
+	Tax         Money
+	FinalPrice  Money
+	Status      OrderStatus
+	CreatedAt   time.Time
+	PaidAt      *time.Time
+	ShippedAt   *time.Time
+	DeliveredAt *time.Time
+	CancelledAt *time.Time
+	RefundedAt  *time.Time
+	// Version is bumped on every persisted change and used for optimistic locking.
+	Version int64
+	// CreatedBy is the authenticated subject that created the order, if any.
+	CreatedBy string
 }
 
-func NewOrder(id string, price float64, tax float64) (*Order, error) {
+func NewOrder(id string, currency string, items []OrderItem) (*Order, error) {
 	order := &Order{
-		ID:    id,
-		Price: price,
-		Tax:   tax,
+		ID:        id,
+		Currency:  currency,
+		Items:     items,
+		Status:    OrderStatusPending,
+		CreatedAt: time.Now().UTC(),
+		Version:   1,
 	}
 	err := order.IsValid()
 	if err != nil {
@@ -22,24 +46,76 @@ func NewOrder(id string, price float64, tax float64) (*Order, error) {
 	return order, nil
 }
 
+// IsValid returns a *ValidationError listing every invalid field.
 func (o *Order) IsValid() error {
+	var errs ValidationError
 	if o.ID == "" {
-		return errors.New("invalid id")
+		errs.Add("id", ErrRequired)
 	}
-	if o.Price <= 0 {
-		return errors.New("invalid price")
+	if !IsValidCurrency(o.Currency) {
+		errs.Add("currency", ErrInvalidCurrency)
 	}
-	if o.Tax <= 0 {
-		return errors.New("invalid tax")
+	if len(o.Items) == 0 {
+		errs.Add("items", ErrNoItems)
 	}
-	return nil
+	for i, item := range o.Items {
+		if err := item.IsValid(); err != nil {
+			errs.Merge(itemField(i), err)
+			continue
+		}
+		if IsValidCurrency(o.Currency) && item.UnitPrice.Currency != o.Currency {
+			errs.Add(itemField(i)+".unit_price", ErrCurrencyMismatch)
+		}
+	}
+	return errs.Err()
 }
 
+// CalculateFinalPrice derives Price, Tax and FinalPrice from the line items.
 func (o *Order) CalculateFinalPrice() error {
-	o.FinalPrice = o.Price + o.Tax
 	err := o.IsValid()
 	if err != nil {
 		return err
 	}
+	price := Money{Currency: o.Currency}
+	tax := Money{Currency: o.Currency}
+	for _, item := range o.Items {
+		if price, err = price.Add(item.Subtotal()); err != nil {
+			return totalError(err)
+		}
+		if tax, err = tax.Add(item.Tax()); err != nil {
+			return totalError(err)
+		}
+	}
+	finalPrice, err := price.Add(tax)
+	if err != nil {
+		return totalError(err)
+	}
+	o.Price = price
+	o.Tax = tax
+	o.FinalPrice = finalPrice
+	return nil
+}
+
+// totalError reports totals that do not fit, such as ErrAmountOverflow,
+// against the items, as callers sent amounts too large to add up.
+func totalError(err error) error {
+	var errs ValidationError
+	errs.Add("items", err)
+	return errs.Err()
+}
+
+// Edit replaces the currency and line items of a pending order and
+// recalculates its totals.
+func (o *Order) Edit(currency string, items []OrderItem) error {
+	if o.Status != OrderStatusPending {
+		return ErrOrderNotEditable
+	}
+	edited := *o
+	edited.Currency = currency
+	edited.Items = items
+	if err := edited.CalculateFinalPrice(); err != nil {
+		return err
+	}
+	*o = edited
 	return nil
 }
@@ -0,0 +1,61 @@
+package entity
+
+type OrderItem struct {
+	SKU       string
+	Quantity  int64
+	UnitPrice Money
+	// TaxRateBps is the tax rate in basis points: 1000 means 10%.
+	TaxRateBps int64
+}
+
+func NewOrderItem(sku string, quantity int64, unitPrice Money, taxRateBps int64) (OrderItem, error) {
+	item := OrderItem{
+		SKU:        sku,
+		Quantity:   quantity,
+		UnitPrice:  unitPrice,
+		TaxRateBps: taxRateBps,
+	}
+	if err := item.IsValid(); err != nil {
+		return OrderItem{}, err
+	}
+	return item, nil
+}
+
+// IsValid returns a *ValidationError listing every invalid field.
+func (i OrderItem) IsValid() error {
+	var errs ValidationError
+	if i.SKU == "" {
+		errs.Add("sku", ErrRequired)
+	}
+	if i.Quantity <= 0 {
+		errs.Add("quantity", ErrNotPositive)
+	}
+	if !IsValidCurrency(i.UnitPrice.Currency) {
+		errs.Add("unit_price", ErrInvalidCurrency)
+	} else if !i.UnitPrice.IsPositive() {
+		errs.Add("unit_price", ErrNotPositive)
+	}
+	if i.TaxRateBps < 0 {
+		errs.Add("tax_rate_bps", ErrNegative)
+	}
+	if len(errs.Fields) == 0 {
+		if subtotal, err := i.UnitPrice.Multiply(i.Quantity); err != nil {
+			errs.Add("quantity", err)
+		} else if _, err := subtotal.ApplyRate(i.TaxRateBps); err != nil {
+			errs.Add("tax_rate_bps", err)
+		}
+	}
+	return errs.Err()
+}
+
+// Subtotal is UnitPrice × Quantity. IsValid rejects the items for which it
+// or Tax would overflow.
+func (i OrderItem) Subtotal() Money {
+	subtotal, _ := i.UnitPrice.Multiply(i.Quantity)
+	return subtotal
+}
+
+func (i OrderItem) Tax() Money {
+	tax, _ := i.Subtotal().ApplyRate(i.TaxRateBps)
+	return tax
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+type OrderSortField string
+
+const (
+	OrderSortByCreatedAt  OrderSortField = "created_at"
+	OrderSortByFinalPrice OrderSortField = "final_price"
+)
+
+func (f OrderSortField) IsValid() bool {
+	return f == OrderSortByCreatedAt || f == OrderSortByFinalPrice
+}
+
+// OrderListFilter narrows a listing. Zero values mean "no restriction";
+// prices are final prices in minor units.
+type OrderListFilter struct {
+	Status      OrderStatus
+	MinPrice    *int64
+	MaxPrice    *int64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+// OrderCursor identifies the last order of the previous page. Only the
+// value of the active sort field is compared, with ID breaking ties.
+type OrderCursor struct {
+	CreatedAt  time.Time
+	FinalPrice int64
+	ID         string
+}
+
+type OrderListQuery struct {
+	Filter     OrderListFilter
+	SortBy     OrderSortField
+	Descending bool
+	After      *OrderCursor
+	Limit      int
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// orderTransitions lists, for each status, the statuses an order may move to.
+// Cancelled and refunded are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusShipped, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {OrderStatusRefunded},
+}
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped,
+		OrderStatusDelivered, OrderStatusCancelled, OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Order) Pay() error {
+	return o.transitionTo(OrderStatusPaid)
+}
+
+func (o *Order) Ship() error {
+	return o.transitionTo(OrderStatusShipped)
+}
+
+func (o *Order) Deliver() error {
+	return o.transitionTo(OrderStatusDelivered)
+}
+
+func (o *Order) Cancel() error {
+	return o.transitionTo(OrderStatusCancelled)
+}
+
+func (o *Order) Refund() error {
+	return o.transitionTo(OrderStatusRefunded)
+}
+
+func (o *Order) transitionTo(next OrderStatus) error {
+	if !o.Status.CanTransitionTo(next) {
+		return ErrInvalidStatusTransition
+	}
+	now := time.Now().UTC()
+	switch next {
+	case OrderStatusPaid:
+		o.PaidAt = &now
+	case OrderStatusShipped:
+		o.ShippedAt = &now
+	case OrderStatusDelivered:
+		o.DeliveredAt = &now
+	case OrderStatusCancelled:
+		o.CancelledAt = &now
+	case OrderStatusRefunded:
+		o.RefundedAt = &now
+	}
+	o.Status = next
+	return nil
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenANewOrder_WhenICheckStatus_ThenItShouldBePending(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Equal(t, OrderStatusPending, order.Status)
+	assert.False(t, order.CreatedAt.IsZero())
+}
+
+func TestGivenAPendingOrder_WhenIFollowTheHappyPath_ThenShouldSetEachTimestamp(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+
+	assert.Nil(t, order.Pay())
+	assert.Equal(t, OrderStatusPaid, order.Status)
+	assert.NotNil(t, order.PaidAt)
+
+	assert.Nil(t, order.Ship())
+	assert.Equal(t, OrderStatusShipped, order.Status)
+	assert.NotNil(t, order.ShippedAt)
+
+	assert.Nil(t, order.Deliver())
+	assert.Equal(t, OrderStatusDelivered, order.Status)
+	assert.NotNil(t, order.DeliveredAt)
+
+	assert.Nil(t, order.Refund())
+	assert.Equal(t, OrderStatusRefunded, order.Status)
+	assert.NotNil(t, order.RefundedAt)
+}
+
+func TestGivenAPendingOrder_WhenICancel_ThenShouldBeCancelled(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Nil(t, order.Cancel())
+	assert.Equal(t, OrderStatusCancelled, order.Status)
+	assert.NotNil(t, order.CancelledAt)
+}
+
+func TestGivenAPendingOrder_WhenIShip_ThenShouldReceiveAnError(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Equal(t, ErrInvalidStatusTransition, order.Ship())
+	assert.Equal(t, OrderStatusPending, order.Status)
+	assert.Nil(t, order.ShippedAt)
+}
+
+func TestGivenAPendingOrder_WhenIRefund_ThenShouldReceiveAnError(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Equal(t, ErrInvalidStatusTransition, order.Refund())
+}
+
+func TestGivenAShippedOrder_WhenICancel_ThenShouldReceiveAnError(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Nil(t, order.Pay())
+	assert.Nil(t, order.Ship())
+	assert.Equal(t, ErrInvalidStatusTransition, order.Cancel())
+	assert.Equal(t, OrderStatusShipped, order.Status)
+}
+
+func TestGivenACancelledOrder_WhenIPay_ThenShouldReceiveAnError(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Nil(t, order.Cancel())
+	assert.Equal(t, ErrInvalidStatusTransition, order.Pay())
+}
@@ -1,49 +1,138 @@
 package entity
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+func newValidItem() OrderItem {
+	return OrderItem{SKU: "SKU-1", Quantity: 2, UnitPrice: Money{Amount: 1050, Currency: "BRL"}, TaxRateBps: 1000}
+}
+
 func TestGivenAnEmptyID_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
-	order := Order{}
-	assert.Error(t, order.IsValid(), "invalid id")
+	order := Order{Currency: "BRL", Items: []OrderItem{newValidItem()}}
+	assert.EqualError(t, order.IsValid(), "validation failed: id: is required")
+	assert.ErrorIs(t, order.IsValid(), ErrRequired)
+}
+
+func TestGivenAnInvalidCurrency_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
+	order := Order{ID: "123", Currency: "real", Items: []OrderItem{newValidItem()}}
+	assert.ErrorIs(t, order.IsValid(), ErrInvalidCurrency)
+}
+
+func TestGivenNoItems_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
+	order := Order{ID: "123", Currency: "BRL"}
+	assert.EqualError(t, order.IsValid(), "validation failed: items: must have at least one item")
+}
+
+func TestGivenAnItemWithZeroQuantity_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
+	item := newValidItem()
+	item.Quantity = 0
+	order := Order{ID: "123", Currency: "BRL", Items: []OrderItem{item}}
+	assert.EqualError(t, order.IsValid(), "validation failed: items[0].quantity: must be greater than zero")
+}
+
+func TestGivenAnItemInAnotherCurrency_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
+	item := newValidItem()
+	item.UnitPrice.Currency = "USD"
+	order := Order{ID: "123", Currency: "BRL", Items: []OrderItem{item}}
+	assert.ErrorIs(t, order.IsValid(), ErrCurrencyMismatch)
 }
 
-func TestGivenAnEmptyPrice_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
-	order := Order{ID: "123"}
-	assert.Error(t, order.IsValid(), "invalid price")
+func TestGivenAQuantityWhoseSubtotalOverflows_WhenCreateANewOrder_ThenShouldReceiveAValidationError(t *testing.T) {
+	item := newValidItem()
+	item.Quantity = math.MaxInt64 / 100
+	order := Order{ID: "123", Currency: "BRL", Items: []OrderItem{item}}
+	assert.EqualError(t, order.IsValid(), "validation failed: items[0].quantity: amount is too large")
+}
+
+func TestGivenItemsWhoseTotalOverflows_WhenICallCalculatePrice_ThenShouldReceiveAValidationError(t *testing.T) {
+	item := newValidItem()
+	item.Quantity = 1
+	item.TaxRateBps = 0
+	item.UnitPrice.Amount = math.MaxInt64 / 2
+	order := Order{ID: "123", Currency: "BRL", Items: []OrderItem{item, item, item}}
+
+	err := order.CalculateFinalPrice()
+
+	var validation *ValidationError
+	assert.ErrorAs(t, err, &validation)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	assert.Equal(t, int64(0), order.FinalPrice.Amount)
 }
 
-func TestGivenAnEmptyTax_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
-	order := Order{ID: "123", Price: 10}
-	assert.Error(t, order.IsValid(), "invalid tax")
+func TestGivenSeveralInvalidFields_WhenValidate_ThenShouldReportEachField(t *testing.T) {
+	item := newValidItem()
+	item.SKU = ""
+	item.UnitPrice.Amount = 0
+	order := Order{Currency: "BRL", Items: []OrderItem{newValidItem(), item}}
+
+	var validation *ValidationError
+	assert.ErrorAs(t, order.IsValid(), &validation)
+	assert.Equal(t, []FieldError{
+		{Field: "id", Err: ErrRequired},
+		{Field: "items[1].sku", Err: ErrRequired},
+		{Field: "items[1].unit_price", Err: ErrNotPositive},
+	}, validation.Fields)
 }
 
 func TestGivenAValidParams_WhenICallNewOrder_ThenIShouldReceiveCreateOrderWithAllParams(t *testing.T) {
 	order := Order{
-		ID:    "123",
-		Price: 10.0,
-		Tax:   2.0,
+		ID:       "123",
+		Currency: "BRL",
+		Items:    []OrderItem{newValidItem()},
 	}
 	assert.Equal(t, "123", order.ID)
-	assert.Equal(t, 10.0, order.Price)
-	assert.Equal(t, 2.0, order.Tax)
+	assert.Equal(t, "BRL", order.Currency)
+	assert.Len(t, order.Items, 1)
 	assert.Nil(t, order.IsValid())
 }
 
 func TestGivenAValidParams_WhenICallNewOrderFunc_ThenIShouldReceiveCreateOrderWithAllParams(t *testing.T) {
-	order, err := NewOrder("123", 10.0, 2.0)
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
 	assert.Nil(t, err)
 	assert.Equal(t, "123", order.ID)
-	assert.Equal(t, 10.0, order.Price)
-	assert.Equal(t, 2.0, order.Tax)
+	assert.Equal(t, "BRL", order.Currency)
+	assert.Equal(t, newValidItem(), order.Items[0])
+}
+
+func TestGivenLineItems_WhenICallCalculatePrice_ThenIShouldSetFinalPriceFromItems(t *testing.T) {
+	second := OrderItem{SKU: "SKU-2", Quantity: 3, UnitPrice: Money{Amount: 333, Currency: "BRL"}, TaxRateBps: 750}
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem(), second})
+	assert.Nil(t, err)
+	assert.Nil(t, order.CalculateFinalPrice())
+	// 2 x 10.50 + 3 x 3.33 = 30.99; tax 2.10 + 0.75 (0.74925 rounded) = 2.85
+	assert.Equal(t, Money{Amount: 3099, Currency: "BRL"}, order.Price)
+	assert.Equal(t, Money{Amount: 285, Currency: "BRL"}, order.Tax)
+	assert.Equal(t, Money{Amount: 3384, Currency: "BRL"}, order.FinalPrice)
+}
+
+func TestGivenAPendingOrder_WhenIEdit_ThenShouldRecalculateTotals(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Nil(t, order.CalculateFinalPrice())
+
+	item := OrderItem{SKU: "SKU-9", Quantity: 1, UnitPrice: Money{Amount: 500, Currency: "USD"}, TaxRateBps: 0}
+	assert.Nil(t, order.Edit("USD", []OrderItem{item}))
+	assert.Equal(t, "USD", order.Currency)
+	assert.Equal(t, Money{Amount: 500, Currency: "USD"}, order.FinalPrice)
 }
 
-func TestGivenAPriceAndTax_WhenICallCalculatePrice_ThenIShouldSetFinalPrice(t *testing.T) {
-	order, err := NewOrder("123", 10.0, 2.0)
+func TestGivenAnInvalidEdit_WhenIEdit_ThenShouldKeepTheOrderUnchanged(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
 	assert.Nil(t, err)
 	assert.Nil(t, order.CalculateFinalPrice())
-	assert.Equal(t, 12.0, order.FinalPrice)
+
+	assert.EqualError(t, order.Edit("BRL", nil), "validation failed: items: must have at least one item")
+	assert.Len(t, order.Items, 1)
+	assert.Equal(t, int64(2310), order.FinalPrice.Amount)
+}
+
+func TestGivenAPaidOrder_WhenIEdit_ThenShouldReceiveAnError(t *testing.T) {
+	order, err := NewOrder("123", "BRL", []OrderItem{newValidItem()})
+	assert.Nil(t, err)
+	assert.Nil(t, order.Pay())
+	assert.Equal(t, ErrOrderNotEditable, order.Edit("BRL", []OrderItem{newValidItem()}))
 }
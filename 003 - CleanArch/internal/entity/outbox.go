@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// OutboxMessage is an event stored in the same transaction as the change that
+// produced it, waiting to be relayed to the message broker.
+type OutboxMessage struct {
+	ID            string
+	AggregateID   string
+	EventName     string
+	Payload       []byte
+	CreatedAt     time.Time
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	PublishedAt   *time.Time
+	// DeadAt is set when the relay gives up on the message.
+	DeadAt *time.Time
+}
+
+func NewOutboxMessage(id, aggregateID, eventName string, payload []byte) OutboxMessage {
+	now := time.Now().UTC()
+	return OutboxMessage{
+		ID:            id,
+		AggregateID:   aggregateID,
+		EventName:     eventName,
+		Payload:       payload,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+}
+
+// OutboxStats summarises the outbox for monitoring. Failing counts pending
+// messages that have been attempted at least once; Dead counts the ones the
+// relay gave up on, which are no longer pending.
+type OutboxStats struct {
+	Pending         int64
+	Failing         int64
+	Published       int64
+	Dead            int64
+	OldestPendingAt *time.Time
+}
+
+type OutboxRepositoryInterface interface {
+	// FetchPending returns up to limit messages that are neither published
+	// nor dead and are due at or before now, oldest first.
+	FetchPending(ctx context.Context, now time.Time, limit int) ([]OutboxMessage, error)
+	MarkPublished(ctx context.Context, id string, at time.Time) error
+	MarkFailed(ctx context.Context, id string, reason string, nextAttemptAt time.Time) error
+	// MarkDead records a final failed attempt; the message is not retried.
+	MarkDead(ctx context.Context, id string, reason string, at time.Time) error
+	Stats(ctx context.Context) (OutboxStats, error)
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrRequired    = errors.New("is required")
+	ErrNotPositive = errors.New("must be greater than zero")
+	ErrNegative    = errors.New("must not be negative")
+	ErrTooLong     = errors.New("is too long")
+	// ErrNeedsItems rejects a currency change that would relabel the stored
+	// amounts instead of repricing them.
+	ErrNeedsItems = errors.New("can only change together with items priced in the new currency")
+)
+
+// FieldError is the reason one input field was rejected. Field is the JSON
+// path of the field in the order input, such as "items[0].quantity".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every invalid field of an entity, so callers can
+// report them all at once. errors.Is matches the reason of any field.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, field := range e.Fields {
+		errs[i] = field
+	}
+	return errs
+}
+
+// Add records err against field.
+func (e *ValidationError) Add(field string, err error) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Err: err})
+}
+
+// Merge records the fields of err under prefix, or err itself against prefix
+// when it is not a ValidationError.
+func (e *ValidationError) Merge(prefix string, err error) {
+	var nested *ValidationError
+	if !errors.As(err, &nested) {
+		e.Add(prefix, err)
+		return
+	}
+	for _, field := range nested.Fields {
+		e.Add(prefix+"."+field.Field, field.Err)
+	}
+}
+
+// Err returns e when it holds at least one field, nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func itemField(index int) string {
+	return fmt.Sprintf("items[%d]", index)
+}
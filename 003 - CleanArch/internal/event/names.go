@@ -0,0 +1,13 @@
+package event
+
+// OrderEventNames lists every event an order emits over its lifecycle.
+var OrderEventNames = []string{
+	"OrderCreated",
+	"OrderPaid",
+	"OrderShipped",
+	"OrderDelivered",
+	"OrderCancelled",
+	"OrderRefunded",
+	"OrderUpdated",
+	"OrderDeleted",
+}
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
+
+type OrderCancelled struct {
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
+}
+
+func NewOrderCancelled() *OrderCancelled {
+	return &OrderCancelled{
+		Name: "OrderCancelled",
+	}
+}
+
+func NewOrderCancelledFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderCancelled()
+	}
+}
+
+func (e *OrderCancelled) GetName() string {
+	return e.Name
+}
+
+func (e *OrderCancelled) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderCancelled) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderCancelled) GetDateTime() time.Time {
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderCancelled) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderCancelled) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
+}
@@ -1,10 +1,15 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
 
 type OrderCreated struct {
-	Name    string
-	Payload interface{}
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
 }
 
 func NewOrderCreated() *OrderCreated {
@@ -13,6 +18,12 @@ func NewOrderCreated() *OrderCreated {
 	}
 }
 
+func NewOrderCreatedFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderCreated()
+	}
+}
+
 func (e *OrderCreated) GetName() string {
 	return e.Name
 }
@@ -26,5 +37,13 @@ func (e *OrderCreated) SetPayload(payload interface{}) {
 }
 
 func (e *OrderCreated) GetDateTime() time.Time {
-	return time.Now()
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderCreated) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderCreated) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
 }
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
+
+type OrderDeleted struct {
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
+}
+
+func NewOrderDeleted() *OrderDeleted {
+	return &OrderDeleted{
+		Name: "OrderDeleted",
+	}
+}
+
+func NewOrderDeletedFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderDeleted()
+	}
+}
+
+func (e *OrderDeleted) GetName() string {
+	return e.Name
+}
+
+func (e *OrderDeleted) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderDeleted) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderDeleted) GetDateTime() time.Time {
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderDeleted) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderDeleted) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
+}
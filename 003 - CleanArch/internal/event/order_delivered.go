@@ -0,0 +1,49 @@
+package event
+
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
+
+type OrderDelivered struct {
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
+}
+
+func NewOrderDelivered() *OrderDelivered {
+	return &OrderDelivered{
+		Name: "OrderDelivered",
+	}
+}
+
+func NewOrderDeliveredFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderDelivered()
+	}
+}
+
+func (e *OrderDelivered) GetName() string {
+	return e.Name
+}
+
+func (e *OrderDelivered) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderDelivered) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderDelivered) GetDateTime() time.Time {
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderDelivered) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderDelivered) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
+}
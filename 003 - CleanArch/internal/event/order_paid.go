@@ -0,0 +1,49 @@
+package event
+
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
+
+type OrderPaid struct {
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
+}
+
+func NewOrderPaid() *OrderPaid {
+	return &OrderPaid{
+		Name: "OrderPaid",
+	}
+}
+
+func NewOrderPaidFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderPaid()
+	}
+}
+
+func (e *OrderPaid) GetName() string {
+	return e.Name
+}
+
+func (e *OrderPaid) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderPaid) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderPaid) GetDateTime() time.Time {
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderPaid) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderPaid) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
+}
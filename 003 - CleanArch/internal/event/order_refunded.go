@@ -0,0 +1,49 @@
+package event
+
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
+
+type OrderRefunded struct {
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
+}
+
+func NewOrderRefunded() *OrderRefunded {
+	return &OrderRefunded{
+		Name: "OrderRefunded",
+	}
+}
+
+func NewOrderRefundedFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderRefunded()
+	}
+}
+
+func (e *OrderRefunded) GetName() string {
+	return e.Name
+}
+
+func (e *OrderRefunded) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderRefunded) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderRefunded) GetDateTime() time.Time {
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderRefunded) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderRefunded) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
+}
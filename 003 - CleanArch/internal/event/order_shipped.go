@@ -0,0 +1,49 @@
+package event
+
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
+
+type OrderShipped struct {
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
+}
+
+func NewOrderShipped() *OrderShipped {
+	return &OrderShipped{
+		Name: "OrderShipped",
+	}
+}
+
+func NewOrderShippedFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderShipped()
+	}
+}
+
+func (e *OrderShipped) GetName() string {
+	return e.Name
+}
+
+func (e *OrderShipped) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderShipped) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderShipped) GetDateTime() time.Time {
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderShipped) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderShipped) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
+}
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"time"
+
+	"CleanArch/pkg/events"
+)
+
+type OrderUpdated struct {
+	Name     string
+	Payload  interface{}
+	Metadata events.Metadata
+}
+
+func NewOrderUpdated() *OrderUpdated {
+	return &OrderUpdated{
+		Name: "OrderUpdated",
+	}
+}
+
+func NewOrderUpdatedFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderUpdated()
+	}
+}
+
+func (e *OrderUpdated) GetName() string {
+	return e.Name
+}
+
+func (e *OrderUpdated) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderUpdated) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderUpdated) GetDateTime() time.Time {
+	return e.Metadata.OccurredAt
+}
+
+func (e *OrderUpdated) GetMetadata() events.Metadata {
+	return e.Metadata
+}
+
+func (e *OrderUpdated) SetMetadata(metadata events.Metadata) {
+	e.Metadata = metadata
+}
@@ -0,0 +1,156 @@
+// Package apierror maps domain and use case errors to the error model of
+// each transport: RFC 9457 problem details over HTTP, status codes with
+// errdetails.BadRequest over gRPC and error extensions over GraphQL.
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/auth"
+	"CleanArch/internal/usecase"
+)
+
+// ErrMalformedRequest marks requests the transport could not decode.
+var ErrMalformedRequest = errors.New("malformed request")
+
+// Malformed marks err, raised while decoding a request, as ErrMalformedRequest.
+func Malformed(err error) error {
+	return fmt.Errorf("%w: %w", ErrMalformedRequest, err)
+}
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalidArgument
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindAlreadyExists
+	KindUnprocessable
+	KindFailedPrecondition
+	KindPreconditionRequired
+	KindTimeout
+	KindCanceled
+	KindUnauthenticated
+	KindPermissionDenied
+	KindTooLarge
+)
+
+// FieldViolation is one invalid input field.
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Problem is the transport neutral description of an error.
+type Problem struct {
+	Kind   Kind
+	Title  string
+	Detail string
+	Fields []FieldViolation
+}
+
+// From classifies err. Unknown errors become KindInternal with a generic
+// detail, so internals never leak to clients.
+func From(err error) Problem {
+	var validation *entity.ValidationError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &validation):
+		fields := make([]FieldViolation, len(validation.Fields))
+		for i, field := range validation.Fields {
+			fields[i] = FieldViolation{Field: field.Field, Message: field.Err.Error()}
+		}
+		return Problem{Kind: KindValidation, Title: "Validation failed", Detail: err.Error(), Fields: fields}
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return Problem{Kind: KindUnauthenticated, Title: "Unauthorized", Detail: auth.ErrUnauthenticated.Error()}
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return Problem{Kind: KindPermissionDenied, Title: "Forbidden", Detail: err.Error()}
+	case errors.Is(err, entity.ErrOrderNotFound):
+		return Problem{Kind: KindNotFound, Title: "Not found", Detail: err.Error()}
+	case errors.Is(err, entity.ErrOrderAlreadyExists):
+		return Problem{Kind: KindAlreadyExists, Title: "Conflict", Detail: err.Error()}
+	case errors.Is(err, entity.ErrIdempotencyKeyReused):
+		return Problem{Kind: KindUnprocessable, Title: "Idempotency key reused", Detail: err.Error()}
+	case errors.Is(err, entity.ErrVersionConflict),
+		errors.Is(err, entity.ErrIdempotencyKeyInProgress):
+		return Problem{Kind: KindConflict, Title: "Conflict", Detail: err.Error()}
+	case errors.Is(err, entity.ErrInvalidStatusTransition),
+		errors.Is(err, entity.ErrOrderNotEditable):
+		return Problem{Kind: KindFailedPrecondition, Title: "Conflict", Detail: err.Error()}
+	case errors.Is(err, usecase.ErrVersionRequired):
+		return Problem{Kind: KindPreconditionRequired, Title: "Precondition required", Detail: err.Error()}
+	case errors.As(err, &tooLarge):
+		return Problem{Kind: KindTooLarge, Title: "Request too large",
+			Detail: fmt.Sprintf("the request body exceeds %d bytes", tooLarge.Limit)}
+	case errors.Is(err, ErrMalformedRequest),
+		errors.Is(err, usecase.ErrInvalidCursor),
+		errors.Is(err, usecase.ErrInvalidSortField),
+		errors.Is(err, usecase.ErrInvalidStatus):
+		return Problem{Kind: KindInvalidArgument, Title: "Bad request", Detail: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return Problem{Kind: KindTimeout, Title: "Timeout", Detail: "the operation did not finish in time"}
+	case errors.Is(err, context.Canceled):
+		return Problem{Kind: KindCanceled, Title: "Canceled", Detail: "the request was canceled"}
+	default:
+		return Problem{Kind: KindInternal, Title: "Internal error", Detail: "internal error"}
+	}
+}
+
+// HTTPStatus is the response status for the problem.
+func (p Problem) HTTPStatus() int {
+	switch p.Kind {
+	case KindInvalidArgument:
+		return http.StatusBadRequest
+	case KindValidation, KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case KindUnauthenticated:
+		return http.StatusUnauthorized
+	case KindPermissionDenied:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict, KindAlreadyExists, KindFailedPrecondition:
+		return http.StatusConflict
+	case KindPreconditionRequired:
+		return http.StatusPreconditionRequired
+	case KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindCanceled:
+		// Non-standard, popularized by nginx: the client closed the request.
+		return 499
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GraphQLCode is the extensions.code of the GraphQL error.
+func (p Problem) GraphQLCode() string {
+	switch p.Kind {
+	case KindInvalidArgument, KindValidation, KindUnprocessable, KindPreconditionRequired, KindTooLarge:
+		return "BAD_USER_INPUT"
+	case KindUnauthenticated:
+		return "UNAUTHENTICATED"
+	case KindPermissionDenied:
+		return "FORBIDDEN"
+	case KindNotFound:
+		return "NOT_FOUND"
+	case KindConflict, KindAlreadyExists:
+		return "CONFLICT"
+	case KindFailedPrecondition:
+		return "FAILED_PRECONDITION"
+	case KindTimeout:
+		return "TIMEOUT"
+	case KindCanceled:
+		return "CANCELED"
+	default:
+		return "INTERNAL_SERVER_ERROR"
+	}
+}
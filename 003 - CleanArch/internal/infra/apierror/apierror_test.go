@@ -0,0 +1,93 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/auth"
+	"CleanArch/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newValidationError() error {
+	order := entity.Order{Currency: "BRL", Items: []entity.OrderItem{{SKU: "SKU-1", Quantity: 0,
+		UnitPrice: entity.Money{Amount: 100, Currency: "BRL"}}}}
+	return order.IsValid()
+}
+
+func TestGivenDomainErrors_WhenFrom_ThenShouldMapEachTransportStatus(t *testing.T) {
+	for _, tc := range []struct {
+		err         error
+		httpStatus  int
+		grpcCode    codes.Code
+		graphQLCode string
+	}{
+		{newValidationError(), http.StatusUnprocessableEntity, codes.InvalidArgument, "BAD_USER_INPUT"},
+		{Malformed(errors.New("unexpected EOF")), http.StatusBadRequest, codes.InvalidArgument, "BAD_USER_INPUT"},
+		{Malformed(&http.MaxBytesError{Limit: 1024}), http.StatusRequestEntityTooLarge, codes.ResourceExhausted, "BAD_USER_INPUT"},
+		{usecase.ErrInvalidCursor, http.StatusBadRequest, codes.InvalidArgument, "BAD_USER_INPUT"},
+		{fmt.Errorf("order 1: %w", entity.ErrOrderNotFound), http.StatusNotFound, codes.NotFound, "NOT_FOUND"},
+		{entity.ErrVersionConflict, http.StatusConflict, codes.Aborted, "CONFLICT"},
+		{entity.ErrOrderAlreadyExists, http.StatusConflict, codes.AlreadyExists, "CONFLICT"},
+		{entity.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, codes.InvalidArgument, "BAD_USER_INPUT"},
+		{entity.ErrIdempotencyKeyInProgress, http.StatusConflict, codes.Aborted, "CONFLICT"},
+		{entity.ErrInvalidStatusTransition, http.StatusConflict, codes.FailedPrecondition, "FAILED_PRECONDITION"},
+		{usecase.ErrVersionRequired, http.StatusPreconditionRequired, codes.InvalidArgument, "BAD_USER_INPUT"},
+		{fmt.Errorf("%w: token is expired", auth.ErrUnauthenticated), http.StatusUnauthorized, codes.Unauthenticated, "UNAUTHENTICATED"},
+		{auth.ErrPermissionDenied, http.StatusForbidden, codes.PermissionDenied, "FORBIDDEN"},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, codes.DeadlineExceeded, "TIMEOUT"},
+		{errors.New("connection refused"), http.StatusInternalServerError, codes.Internal, "INTERNAL_SERVER_ERROR"},
+	} {
+		problem := From(tc.err)
+		assert.Equal(t, tc.httpStatus, problem.HTTPStatus(), tc.err.Error())
+		assert.Equal(t, tc.grpcCode, problem.GRPCCode(), tc.err.Error())
+		assert.Equal(t, tc.graphQLCode, problem.GraphQLCode(), tc.err.Error())
+	}
+}
+
+func TestGivenAnInternalError_WhenFrom_ThenShouldHideItsMessage(t *testing.T) {
+	problem := From(errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+	assert.Equal(t, "internal error", problem.Detail)
+}
+
+func TestGivenAValidationError_WhenWriteHTTP_ThenShouldWriteProblemDetails(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/order", nil)
+
+	WriteHTTP(recorder, request, newValidationError())
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+	var body ProblemDetails
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+	assert.Equal(t, "about:blank", body.Type)
+	assert.Equal(t, http.StatusUnprocessableEntity, body.Status)
+	assert.Equal(t, "/order", body.Instance)
+	assert.Equal(t, []FieldViolation{
+		{Field: "id", Message: "is required"},
+		{Field: "items[0].quantity", Message: "must be greater than zero"},
+	}, body.Errors)
+}
+
+func TestGivenAValidationError_WhenGRPCError_ThenShouldAttachBadRequest(t *testing.T) {
+	st, ok := status.FromError(GRPCError(newValidationError()))
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 2)
+	assert.Equal(t, "items[0].quantity", badRequest.FieldViolations[1].Field)
+	assert.Equal(t, "must be greater than zero", badRequest.FieldViolations[1].Description)
+}
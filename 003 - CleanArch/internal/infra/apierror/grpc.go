@@ -0,0 +1,65 @@
+package apierror
+
+import (
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCCode is the status code for the problem.
+func (p Problem) GRPCCode() codes.Code {
+	switch p.Kind {
+	case KindInvalidArgument, KindValidation, KindUnprocessable, KindPreconditionRequired:
+		return codes.InvalidArgument
+	case KindUnauthenticated:
+		return codes.Unauthenticated
+	case KindPermissionDenied:
+		return codes.PermissionDenied
+	case KindNotFound:
+		return codes.NotFound
+	case KindConflict:
+		return codes.Aborted
+	case KindAlreadyExists:
+		return codes.AlreadyExists
+	case KindFailedPrecondition:
+		return codes.FailedPrecondition
+	case KindTooLarge:
+		return codes.ResourceExhausted
+	case KindTimeout:
+		return codes.DeadlineExceeded
+	case KindCanceled:
+		return codes.Canceled
+	default:
+		return codes.Internal
+	}
+}
+
+// GRPCError converts err to a status error. Validation errors carry an
+// errdetails.BadRequest with one violation per field.
+func GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	problem := From(err)
+	if problem.Kind == KindInternal {
+		log.Printf("grpc: %v", err)
+	}
+	st := status.New(problem.GRPCCode(), problem.Detail)
+	if len(problem.Fields) == 0 {
+		return st.Err()
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range problem.Fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		})
+	}
+	withDetails, detailsErr := st.WithDetails(badRequest)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
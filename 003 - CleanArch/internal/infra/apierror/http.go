@@ -0,0 +1,42 @@
+package apierror
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is the RFC 9457 body, extended with the invalid fields.
+type ProblemDetails struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Errors   []FieldViolation `json:"errors,omitempty"`
+}
+
+// WriteHTTP writes err as problem details. Internal errors are logged, since
+// the response hides them.
+func WriteHTTP(w http.ResponseWriter, r *http.Request, err error) {
+	problem := From(err)
+	if problem.Kind == KindInternal {
+		log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	}
+	status := problem.HTTPStatus()
+	if problem.Kind == KindUnauthenticated {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="orders"`)
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ProblemDetails{
+		Type:     "about:blank",
+		Title:    problem.Title,
+		Status:   status,
+		Detail:   problem.Detail,
+		Instance: r.URL.Path,
+		Errors:   problem.Fields,
+	})
+}
@@ -0,0 +1,129 @@
+// Package auth verifies JWT bearer tokens and carries the authenticated
+// principal through the request context. Transports enforce it: chi
+// middleware, a gRPC interceptor and a GraphQL directive.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Permissions granted through the roles (or scope) claim.
+const (
+	PermissionRead  = "orders:read"
+	PermissionWrite = "orders:write"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("missing or invalid bearer token")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Principal is the authenticated caller.
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+// Can reports whether the principal holds permission. orders:write implies
+// orders:read.
+func (p Principal) Can(permission string) bool {
+	if slices.Contains(p.Roles, permission) {
+		return true
+	}
+	return permission == PermissionRead && slices.Contains(p.Roles, PermissionWrite)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Subject returns the subject of the principal in ctx, or "" when the
+// request is unauthenticated.
+func Subject(ctx context.Context) string {
+	principal, _ := PrincipalFrom(ctx)
+	return principal.Subject
+}
+
+// Authorize returns ErrUnauthenticated when ctx carries no principal and
+// ErrPermissionDenied when the principal lacks permission.
+func Authorize(ctx context.Context, permission string) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if !principal.Can(permission) {
+		return fmt.Errorf("%w: %s required", ErrPermissionDenied, permission)
+	}
+	return nil
+}
+
+// Claims accepts roles either as a "roles" array or as a space separated
+// OAuth 2 "scope".
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+	Scope string   `json:"scope,omitempty"`
+}
+
+type VerifierOptions struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+type Verifier struct {
+	Keys   *KeySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(keys *KeySet, options VerifierOptions) *Verifier {
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(keys.Algorithms()),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(options.Leeway),
+	}
+	if options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.Issuer))
+	}
+	if options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.Audience))
+	}
+	return &Verifier{Keys: keys, parser: jwt.NewParser(parserOptions...)}
+}
+
+// Verify checks the signature and registered claims of token.
+func (v *Verifier) Verify(token string) (Principal, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.Keys.Keyfunc); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+	if claims.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	}
+	roles := append([]string(nil), claims.Roles...)
+	roles = append(roles, strings.Fields(claims.Scope)...)
+	return Principal{Subject: claims.Subject, Roles: roles}, nil
+}
+
+// VerifyAuthorization verifies an "Authorization: Bearer <token>" value.
+func (v *Verifier) VerifyAuthorization(header string) (Principal, error) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+	return v.Verify(strings.TrimSpace(token))
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClaims(subject string, roles ...string) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    "https://auth.example.com",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: roles,
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestGivenASharedSecret_WhenVerify_ThenShouldReturnThePrincipal(t *testing.T) {
+	keys, err := NewStaticKeySet("s3cret")
+	require.NoError(t, err)
+	verifier := NewVerifier(keys, VerifierOptions{Issuer: "https://auth.example.com"})
+
+	claims := newClaims("user-1", PermissionWrite)
+	claims.Scope = "profile"
+	principal, err := verifier.VerifyAuthorization("Bearer " + sign(t, jwt.SigningMethodHS256, "", []byte("s3cret"), claims))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", principal.Subject)
+	assert.Equal(t, []string{PermissionWrite, "profile"}, principal.Roles)
+	assert.True(t, principal.Can(PermissionRead))
+	assert.True(t, principal.Can(PermissionWrite))
+}
+
+func TestGivenInvalidTokens_WhenVerify_ThenShouldReturnErrUnauthenticated(t *testing.T) {
+	keys, err := NewStaticKeySet("s3cret")
+	require.NoError(t, err)
+	verifier := NewVerifier(keys, VerifierOptions{Issuer: "https://auth.example.com"})
+
+	expired := newClaims("user-1")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	otherIssuer := newClaims("user-1")
+	otherIssuer.Issuer = "https://evil.example.com"
+	noExpiry := newClaims("user-1")
+	noExpiry.ExpiresAt = nil
+
+	for name, header := range map[string]string{
+		"missing scheme": sign(t, jwt.SigningMethodHS256, "", []byte("s3cret"), newClaims("user-1")),
+		"wrong secret":   "Bearer " + sign(t, jwt.SigningMethodHS256, "", []byte("other"), newClaims("user-1")),
+		"expired":        "Bearer " + sign(t, jwt.SigningMethodHS256, "", []byte("s3cret"), expired),
+		"other issuer":   "Bearer " + sign(t, jwt.SigningMethodHS256, "", []byte("s3cret"), otherIssuer),
+		"no expiry":      "Bearer " + sign(t, jwt.SigningMethodHS256, "", []byte("s3cret"), noExpiry),
+		"no subject":     "Bearer " + sign(t, jwt.SigningMethodHS256, "", []byte("s3cret"), newClaims("")),
+		"garbage":        "Bearer not-a-token",
+	} {
+		_, err := verifier.VerifyAuthorization(header)
+		assert.ErrorIs(t, err, ErrUnauthenticated, name)
+	}
+}
+
+func TestGivenAPublicKey_WhenATokenIsSignedWithHMACUsingIt_ThenShouldReject(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	keys, err := NewStaticKeySet(string(publicPEM))
+	require.NoError(t, err)
+	verifier := NewVerifier(keys, VerifierOptions{})
+
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodRS256, "", private, newClaims("user-1")))
+	assert.NoError(t, err)
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodHS256, "", publicPEM, newClaims("user-1")))
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestGivenAJWKS_WhenVerify_ThenShouldPickTheKeyByKid(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encode(ecKey.X.FillBytes(make([]byte, 32))), "y": encode(ecKey.Y.FillBytes(make([]byte, 32)))},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "", "e": ""},
+	}})
+	require.NoError(t, err)
+	keys, err := ParseJWKS(jwks)
+	require.NoError(t, err)
+	verifier := NewVerifier(keys, VerifierOptions{})
+
+	principal, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, newClaims("rsa-user", PermissionRead)))
+	require.NoError(t, err)
+	assert.Equal(t, "rsa-user", principal.Subject)
+	principal, err = verifier.Verify(sign(t, jwt.SigningMethodES256, "ec-1", ecKey, newClaims("ec-user")))
+	require.NoError(t, err)
+	assert.Equal(t, "ec-user", principal.Subject)
+
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodRS256, "unknown", rsaKey, newClaims("rsa-user")))
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodRS256, "", rsaKey, newClaims("rsa-user")))
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestGivenAPrincipal_WhenAuthorize_ThenShouldCheckItsRoles(t *testing.T) {
+	assert.ErrorIs(t, Authorize(context.Background(), PermissionRead), ErrUnauthenticated)
+
+	reader := WithPrincipal(context.Background(), Principal{Subject: "user-1", Roles: []string{PermissionRead}})
+	assert.NoError(t, Authorize(reader, PermissionRead))
+	assert.ErrorIs(t, Authorize(reader, PermissionWrite), ErrPermissionDenied)
+	assert.Equal(t, "user-1", Subject(reader))
+	assert.Equal(t, "", Subject(context.Background()))
+}
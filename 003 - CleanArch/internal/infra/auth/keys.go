@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrNoKeys = errors.New("no usable verification keys")
+
+// KeySet holds the keys tokens may be signed with, indexed by key ID. A
+// token without a kid header is accepted when the set has a single key.
+type KeySet struct {
+	keys       map[string]any
+	algorithms []string
+}
+
+// Algorithms lists the signing methods the keys can verify, so a token can
+// never choose, say, HS256 against a public key.
+func (s *KeySet) Algorithms() []string {
+	return s.algorithms
+}
+
+func (s *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func newKeySet(keys map[string]any) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	seen := map[string]bool{}
+	set := &KeySet{keys: keys}
+	for _, key := range keys {
+		for _, algorithm := range algorithmsFor(key) {
+			if !seen[algorithm] {
+				seen[algorithm] = true
+				set.algorithms = append(set.algorithms, algorithm)
+			}
+		}
+	}
+	return set, nil
+}
+
+func algorithmsFor(key any) []string {
+	switch key.(type) {
+	case []byte:
+		return []string{"HS256", "HS384", "HS512"}
+	case *rsa.PublicKey:
+		return []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	case *ecdsa.PublicKey:
+		return []string{"ES256", "ES384", "ES512"}
+	case ed25519.PublicKey:
+		return []string{"EdDSA"}
+	}
+	return nil
+}
+
+// NewStaticKeySet builds a single key set from value: a PEM encoded public
+// key (RSA, ECDSA or Ed25519), or otherwise an HMAC shared secret.
+func NewStaticKeySet(value string) (*KeySet, error) {
+	if value == "" {
+		return nil, ErrNoKeys
+	}
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return newKeySet(map[string]any{"": []byte(value)})
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("auth: static key: %w", err)
+	}
+	if algorithmsFor(key) == nil {
+		return nil, fmt.Errorf("auth: static key: unsupported key type %T", key)
+	}
+	return newKeySet(map[string]any{"": key})
+}
+
+// LoadJWKS reads a JSON Web Key Set file.
+func LoadJWKS(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS accepts the RSA, EC (P-256, P-384, P-521) and OKP (Ed25519)
+// signing keys of a JSON Web Key Set and skips encryption keys.
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("auth: jwks: %w", err)
+	}
+	keys := map[string]any{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("auth: jwks: key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	return newKeySet(keys)
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.X, "="))
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
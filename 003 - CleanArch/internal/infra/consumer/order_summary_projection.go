@@ -0,0 +1,131 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+)
+
+// OrderSummaryProjection keeps order_summaries, a denormalized row per order
+// with its totals and item count, in sync with order events. Events may
+// arrive out of order, so a row only moves forward in version.
+type OrderSummaryProjection struct {
+	Dialect dialect.Dialect
+}
+
+func NewOrderSummaryProjection(d dialect.Dialect) *OrderSummaryProjection {
+	return &OrderSummaryProjection{Dialect: d}
+}
+
+// orderSummary is a row of order_summaries.
+type orderSummary struct {
+	OrderID    string
+	Status     string
+	Currency   string
+	ItemCount  int64
+	FinalPrice int64
+	Version    int64
+	CreatedAt  time.Time
+}
+
+// StatusDeleted marks the summary of a deleted order. The row is kept as a
+// tombstone so an older event delivered late does not bring it back.
+const StatusDeleted = "deleted"
+
+func (p *OrderSummaryProjection) Handle(ctx context.Context, tx *sql.Tx, message events.Message) error {
+	ce, err := events.ParseCloudEvent(message.Payload)
+	if err != nil {
+		return Permanent(fmt.Errorf("decode %s envelope: %w", message.Name, err))
+	}
+	var summary orderSummary
+	if message.Name == "OrderDeleted" {
+		summary, err = deletedSummary(ce)
+	} else {
+		summary, err = orderSummaryOf(ce)
+	}
+	if err != nil {
+		return Permanent(fmt.Errorf("decode %s payload: %w", message.Name, err))
+	}
+	if summary.OrderID == "" {
+		return Permanent(fmt.Errorf("%s payload without order id", message.Name))
+	}
+	return p.upsert(ctx, tx, summary, ce.Time)
+}
+
+func orderSummaryOf(ce events.CloudEvent) (orderSummary, error) {
+	var order usecase.OrderOutputDTO
+	if err := json.Unmarshal(ce.Data, &order); err != nil {
+		return orderSummary{}, err
+	}
+	var itemCount int64
+	for _, item := range order.Items {
+		itemCount += item.Quantity
+	}
+	return orderSummary{
+		OrderID:    order.ID,
+		Status:     order.Status,
+		Currency:   order.Currency,
+		ItemCount:  itemCount,
+		FinalPrice: order.FinalPrice.Amount,
+		Version:    order.Version,
+		CreatedAt:  order.CreatedAt,
+	}, nil
+}
+
+// deletedSummary is the tombstone for an OrderDeleted event. The event
+// carries the version that was deleted, which the event of the last change
+// shares, so the tombstone takes the one after it.
+func deletedSummary(ce events.CloudEvent) (orderSummary, error) {
+	var deleted usecase.DeleteOrderOutputDTO
+	if err := json.Unmarshal(ce.Data, &deleted); err != nil {
+		return orderSummary{}, err
+	}
+	return orderSummary{
+		OrderID:   deleted.ID,
+		Status:    StatusDeleted,
+		Version:   deleted.Version + 1,
+		CreatedAt: ce.Time,
+	}, nil
+}
+
+func (p *OrderSummaryProjection) upsert(ctx context.Context, tx *sql.Tx, s orderSummary, updatedAt time.Time) error {
+	// upsert spelled out so it runs on every SQL dialect; a tombstone keeps
+	// the totals of the order it replaces
+	set := "status = ?, currency = ?, item_count = ?, final_price = ?, version = ?, updated_at = ?"
+	args := []any{s.Status, s.Currency, s.ItemCount, s.FinalPrice, s.Version, updatedAt}
+	if s.Status == StatusDeleted {
+		set = "status = ?, version = ?, updated_at = ?"
+		args = []any{s.Status, s.Version, updatedAt}
+	}
+	result, err := tx.ExecContext(ctx,
+		p.Dialect.Rebind("UPDATE order_summaries SET "+set+" WHERE order_id = ? AND version <= ?"),
+		append(args, s.OrderID, s.Version)...,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected > 0 {
+		return err
+	}
+	var exists int
+	err = tx.QueryRowContext(ctx, p.Dialect.Rebind("SELECT 1 FROM order_summaries WHERE order_id = ?"), s.OrderID).Scan(&exists)
+	if err == nil {
+		// the stored summary is newer than this event
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		p.Dialect.Rebind("INSERT INTO order_summaries (order_id, status, currency, item_count, final_price, version, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"),
+		s.OrderID, s.Status, s.Currency, s.ItemCount, s.FinalPrice, s.Version, s.CreatedAt, updatedAt,
+	)
+	return err
+}
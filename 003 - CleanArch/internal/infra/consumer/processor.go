@@ -0,0 +1,141 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/pkg/events"
+)
+
+// Outcome tells the transport what to do with a delivery.
+type Outcome int
+
+const (
+	Ack Outcome = iota
+	Retry
+	DeadLetter
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Ack:
+		return "ack"
+	case Retry:
+		return "retry"
+	default:
+		return "dead-letter"
+	}
+}
+
+const DefaultMaxAttempts = 5
+
+// Handler applies one event inside tx. The transaction also records the
+// event ID, so the handler's writes and the dedupe marker commit together.
+type Handler interface {
+	Handle(ctx context.Context, tx *sql.Tx, message events.Message) error
+}
+
+// PermanentError marks a failure that retrying cannot fix, such as a payload
+// that does not decode. It sends the message straight to the dead-letter queue.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// Processor runs the handler registered for each event name exactly once per
+// event ID, tracked in the processed_events table.
+type Processor struct {
+	Db          *sql.DB
+	Dialect     dialect.Dialect
+	Handlers    map[string]Handler
+	MaxAttempts int
+}
+
+func NewProcessor(db *sql.DB, d dialect.Dialect) *Processor {
+	return &Processor{
+		Db:          db,
+		Dialect:     d,
+		Handlers:    make(map[string]Handler),
+		MaxAttempts: DefaultMaxAttempts,
+	}
+}
+
+func (p *Processor) Register(eventName string, handler Handler) {
+	p.Handlers[eventName] = handler
+}
+
+// Process handles a delivery; attempt starts at 1. Events without a handler
+// are acknowledged and skipped.
+func (p *Processor) Process(ctx context.Context, message events.Message, attempt int) Outcome {
+	handler, ok := p.Handlers[message.Name]
+	if !ok {
+		return Ack
+	}
+	if message.ID == "" {
+		log.Printf("consumer: %s without event ID", message.Name)
+		return DeadLetter
+	}
+
+	// the events a handler raises join the chain of the one it handles
+	if ce, err := events.ParseCloudEvent(message.Payload); err == nil {
+		ctx = events.WithCause(ctx, ce.Metadata())
+	}
+	err := p.handleOnce(ctx, handler, message)
+	if err == nil {
+		return Ack
+	}
+	var permanent *PermanentError
+	if errors.As(err, &permanent) || attempt >= p.MaxAttempts {
+		log.Printf("consumer: dead-lettering %s %s after %d attempt(s): %v", message.Name, message.ID, attempt, err)
+		return DeadLetter
+	}
+	log.Printf("consumer: retrying %s %s (attempt %d): %v", message.Name, message.ID, attempt, err)
+	return Retry
+}
+
+func (p *Processor) handleOnce(ctx context.Context, handler Handler, message events.Message) error {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, p.Dialect.Rebind("SELECT 1 FROM processed_events WHERE event_id = ?"), message.ID).Scan(&exists)
+	if err == nil {
+		// already applied; a redelivery after a lost ack
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err := handler.Handle(ctx, tx, message); err != nil {
+		return err
+	}
+	// a concurrent duplicate fails here on the primary key, and the retry then
+	// sees the row above
+	_, err = tx.ExecContext(ctx,
+		p.Dialect.Rebind("INSERT INTO processed_events (event_id, event_name, processed_at) VALUES (?, ?, ?)"),
+		message.ID, message.Name, time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,202 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"CleanArch/internal/event"
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/infra/database/migrations"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+	"github.com/stretchr/testify/suite"
+
+	// sqlite
+	_ "modernc.org/sqlite"
+)
+
+type countingHandler struct {
+	calls int
+	err   error
+}
+
+func (h *countingHandler) Handle(ctx context.Context, tx *sql.Tx, message events.Message) error {
+	h.calls++
+	return h.err
+}
+
+// causeRecorder notes the chain the events it would raise belong to.
+type causeRecorder struct {
+	correlationID, causationID string
+}
+
+func (h *causeRecorder) Handle(ctx context.Context, tx *sql.Tx, message events.Message) error {
+	h.correlationID, h.causationID = events.CorrelationID(ctx), events.CausationID(ctx)
+	return nil
+}
+
+type ProcessorTestSuite struct {
+	suite.Suite
+	Db *sql.DB
+}
+
+func (suite *ProcessorTestSuite) SetupTest() {
+	db, err := sql.Open(dialect.SQLite.DriverName(), ":memory:")
+	suite.NoError(err)
+	db.SetMaxOpenConns(1)
+	migrator, err := migrations.NewMigrator(db, dialect.SQLite)
+	suite.NoError(err)
+	_, err = migrator.Up()
+	suite.NoError(err)
+	suite.Db = db
+}
+
+func (suite *ProcessorTestSuite) TearDownTest() {
+	suite.Db.Close()
+}
+
+func TestProcessorSuite(t *testing.T) {
+	suite.Run(t, new(ProcessorTestSuite))
+}
+
+func (suite *ProcessorTestSuite) TestGivenARedeliveredEvent_WhenProcess_ThenShouldHandleOnce() {
+	handler := &countingHandler{}
+	processor := NewProcessor(suite.Db, dialect.SQLite)
+	processor.Register("OrderCreated", handler)
+	message := events.Message{ID: "1", Name: "OrderCreated"}
+
+	suite.Equal(Ack, processor.Process(context.Background(), message, 1))
+	suite.Equal(Ack, processor.Process(context.Background(), message, 1))
+	suite.Equal(1, handler.calls)
+}
+
+func (suite *ProcessorTestSuite) TestGivenAFailingHandler_WhenProcess_ThenShouldRetryThenDeadLetter() {
+	handler := &countingHandler{err: errors.New("database unavailable")}
+	processor := NewProcessor(suite.Db, dialect.SQLite)
+	processor.MaxAttempts = 3
+	processor.Register("OrderCreated", handler)
+	message := events.Message{ID: "1", Name: "OrderCreated"}
+
+	suite.Equal(Retry, processor.Process(context.Background(), message, 1))
+	suite.Equal(Retry, processor.Process(context.Background(), message, 2))
+	suite.Equal(DeadLetter, processor.Process(context.Background(), message, 3))
+
+	var count int
+	suite.NoError(suite.Db.QueryRow("SELECT count(*) FROM processed_events").Scan(&count))
+	suite.Equal(0, count)
+}
+
+func (suite *ProcessorTestSuite) TestGivenAPermanentError_WhenProcess_ThenShouldDeadLetterImmediately() {
+	processor := NewProcessor(suite.Db, dialect.SQLite)
+	processor.Register("OrderCreated", NewOrderSummaryProjection(dialect.SQLite))
+	message := events.Message{ID: "1", Name: "OrderCreated", Payload: []byte("not json")}
+
+	suite.Equal(DeadLetter, processor.Process(context.Background(), message, 1))
+}
+
+func (suite *ProcessorTestSuite) TestGivenAnUnknownEvent_WhenProcess_ThenShouldAck() {
+	processor := NewProcessor(suite.Db, dialect.SQLite)
+	suite.Equal(Ack, processor.Process(context.Background(), events.Message{ID: "1", Name: "Other"}, 1))
+}
+
+func (suite *ProcessorTestSuite) TestGivenOrderCreatedFromPublisher_WhenProcessed_ThenShouldProjectSummary() {
+	processor := NewProcessor(suite.Db, dialect.SQLite)
+	processor.Register("OrderCreated", NewOrderSummaryProjection(dialect.SQLite))
+	publisher := events.NewInMemoryPublisher()
+	var outcomes []Outcome
+	publisher.Subscribe(func(m events.Message) {
+		outcomes = append(outcomes, processor.Process(context.Background(), m, 1))
+	})
+
+	orderCreated := event.NewOrderCreated()
+	orderCreated.SetPayload(usecase.OrderOutputDTO{
+		ID:         "a",
+		Currency:   "BRL",
+		Status:     "pending",
+		Version:    1,
+		CreatedAt:  time.Now().UTC(),
+		Items:      []usecase.OrderItemOutputDTO{{SKU: "SKU-1", Quantity: 2}, {SKU: "SKU-2", Quantity: 1}},
+		FinalPrice: usecase.MoneyDTO{Amount: 2200, Currency: "BRL"},
+	})
+	orderCreated.SetMetadata(events.NewMetadata(context.Background(), "a"))
+	payload, err := events.MarshalCloudEvent(orderCreated)
+	suite.NoError(err)
+	err = publisher.Publish(context.Background(), events.Message{
+		ID:          orderCreated.GetMetadata().ID,
+		Name:        orderCreated.GetName(),
+		Key:         "a",
+		ContentType: events.CloudEventsContentType,
+		Payload:     payload,
+	})
+	suite.NoError(err)
+	suite.Equal([]Outcome{Ack}, outcomes)
+
+	var status, currency string
+	var itemCount, finalPrice int64
+	err = suite.Db.QueryRow("SELECT status, currency, item_count, final_price FROM order_summaries WHERE order_id = ?", "a").
+		Scan(&status, &currency, &itemCount, &finalPrice)
+	suite.NoError(err)
+	suite.Equal("pending", status)
+	suite.Equal("BRL", currency)
+	suite.Equal(int64(3), itemCount)
+	suite.Equal(int64(2200), finalPrice)
+}
+
+// orderMessage wraps payload in the CloudEvent the outbox relay publishes.
+func (suite *ProcessorTestSuite) orderMessage(newEvent events.EventFactory, payload any) events.Message {
+	evt := newEvent()
+	evt.SetPayload(payload)
+	evt.SetMetadata(events.NewMetadata(context.Background(), "a"))
+	data, err := events.MarshalCloudEvent(evt)
+	suite.NoError(err)
+	return events.Message{ID: evt.GetMetadata().ID, Name: evt.GetName(), Key: "a", Payload: data}
+}
+
+func (suite *ProcessorTestSuite) summary() (status string, version int64) {
+	err := suite.Db.QueryRow("SELECT status, version FROM order_summaries WHERE order_id = ?", "a").Scan(&status, &version)
+	suite.NoError(err)
+	return status, version
+}
+
+func (suite *ProcessorTestSuite) TestGivenOrderEventsOutOfOrder_WhenProcessed_ThenShouldKeepTheNewestSummary() {
+	processor := NewProcessor(suite.Db, dialect.SQLite)
+	projection := NewOrderSummaryProjection(dialect.SQLite)
+	for _, name := range event.OrderEventNames {
+		processor.Register(name, projection)
+	}
+	order := usecase.OrderOutputDTO{ID: "a", Currency: "BRL", Status: "pending", Version: 1, CreatedAt: time.Now().UTC()}
+	created := suite.orderMessage(event.NewOrderCreatedFactory(), order)
+	paid := order
+	paid.Status, paid.Version = "paid", 2
+
+	suite.Equal(Ack, processor.Process(context.Background(), suite.orderMessage(event.NewOrderPaidFactory(), paid), 1))
+	suite.Equal(Ack, processor.Process(context.Background(), created, 1))
+	status, version := suite.summary()
+	suite.Equal("paid", status)
+	suite.Equal(int64(2), version)
+
+	deleted := suite.orderMessage(event.NewOrderDeletedFactory(), usecase.DeleteOrderOutputDTO{ID: "a", Version: 2})
+	suite.Equal(Ack, processor.Process(context.Background(), deleted, 1))
+	suite.Equal(Ack, processor.Process(context.Background(), suite.orderMessage(event.NewOrderPaidFactory(), paid), 1))
+	status, _ = suite.summary()
+	suite.Equal(StatusDeleted, status)
+}
+
+func (suite *ProcessorTestSuite) TestGivenAnEventFromARequest_WhenHandled_ThenShouldBeTheCauseOfTheNextEvents() {
+	handler := &causeRecorder{}
+	processor := NewProcessor(suite.Db, dialect.SQLite)
+	processor.Register("OrderCreated", handler)
+	evt := event.NewOrderCreated()
+	evt.SetPayload(usecase.OrderOutputDTO{ID: "a"})
+	evt.SetMetadata(events.NewMetadata(events.WithCorrelationID(context.Background(), "request-1"), "a"))
+	payload, err := events.MarshalCloudEvent(evt)
+	suite.NoError(err)
+
+	message := events.Message{ID: evt.GetMetadata().ID, Name: evt.GetName(), Payload: payload}
+	suite.Equal(Ack, processor.Process(context.Background(), message, 1))
+	suite.Equal("request-1", handler.correlationID)
+	suite.Equal(evt.GetMetadata().ID, handler.causationID)
+}
@@ -0,0 +1,137 @@
+// Package dialect hides the differences between the supported SQL databases:
+// driver names, DSN formats and placeholder syntax.
+package dialect
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite3"
+)
+
+var ErrUnsupported = errors.New("unsupported database driver")
+
+// Parse maps a DB_DRIVER value to its dialect.
+func Parse(driver string) (Dialect, error) {
+	switch strings.ToLower(driver) {
+	case "mysql":
+		return MySQL, nil
+	case "postgres", "postgresql", "pgx":
+		return Postgres, nil
+	case "sqlite3", "sqlite":
+		return SQLite, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupported, driver)
+}
+
+// DriverName is the name the dialect's database/sql driver registers under.
+// SQLite uses modernc.org/sqlite, written in Go, so builds need no cgo.
+func (d Dialect) DriverName() string {
+	switch d {
+	case Postgres:
+		return "pgx"
+	case SQLite:
+		return "sqlite"
+	}
+	return string(d)
+}
+
+// Rebind rewrites the ? placeholders of query into the dialect's syntax.
+// Queries must not contain a literal question mark.
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// IsUniqueViolation reports whether err is the dialect's duplicate key error.
+func (d Dialect) IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch d {
+	case MySQL:
+		var mysqlErr *mysql.MySQLError
+		return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+	case Postgres:
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr) && pgErr.Code == "23505"
+	case SQLite:
+		// Matched by message so this package does not import the driver.
+		return strings.Contains(err.Error(), "UNIQUE constraint failed")
+	}
+	return false
+}
+
+// DSNConfig holds the connection settings read from the environment. For
+// SQLite, Name is the database file.
+type DSNConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+}
+
+// DSN builds the data source name for the dialect's driver, escaping the
+// credentials as each format requires.
+func (d Dialect) DSN(config DSNConfig) string {
+	switch d {
+	case MySQL:
+		c := mysql.NewConfig()
+		c.User = config.User
+		c.Passwd = config.Password
+		c.Net = "tcp"
+		c.Addr = net.JoinHostPort(config.Host, config.Port)
+		c.DBName = config.Name
+		c.ParseTime = true
+		return c.FormatDSN()
+	case Postgres:
+		query := url.Values{}
+		if config.SSLMode != "" {
+			query.Set("sslmode", config.SSLMode)
+		}
+		u := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(config.User, config.Password),
+			Host:     net.JoinHostPort(config.Host, config.Port),
+			Path:     "/" + config.Name,
+			RawQuery: query.Encode(),
+		}
+		return u.String()
+	case SQLite:
+		name := config.Name
+		if name == "" {
+			name = ":memory:"
+		}
+		// _time_format=sqlite stores times as "2006-01-02 15:04:05.999999999-07:00"
+		return "file:" + name + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_time_format=sqlite"
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package dialect
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenADriverName_WhenParse_ThenShouldReturnItsDialect(t *testing.T) {
+	for driver, want := range map[string]Dialect{
+		"mysql": MySQL, "postgres": Postgres, "pgx": Postgres, "sqlite3": SQLite, "sqlite": SQLite,
+	} {
+		got, err := Parse(driver)
+		assert.NoError(t, err, driver)
+		assert.Equal(t, want, got, driver)
+	}
+	_, err := Parse("oracle")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestGivenPostgres_WhenRebind_ThenShouldNumberPlaceholders(t *testing.T) {
+	query := "SELECT id FROM orders WHERE status = ? AND id IN (?, ?)"
+	assert.Equal(t, "SELECT id FROM orders WHERE status = $1 AND id IN ($2, $3)", Postgres.Rebind(query))
+	assert.Equal(t, query, MySQL.Rebind(query))
+	assert.Equal(t, query, SQLite.Rebind(query))
+}
+
+func TestGivenCredentialsWithSpecialCharacters_WhenDSN_ThenShouldEscapeThem(t *testing.T) {
+	config := DSNConfig{Host: "db", Port: "5432", User: "app", Password: "p@ss/word", Name: "orders", SSLMode: "disable"}
+
+	assert.Equal(t, "postgres://app:p%40ss%2Fword@db:5432/orders?sslmode=disable", Postgres.DSN(config))
+	config.Port = "3306"
+	assert.Equal(t, "app:p@ss/word@tcp(db:3306)/orders?parseTime=true", MySQL.DSN(config))
+	assert.Equal(t, "file:orders.db?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_time_format=sqlite", SQLite.DSN(DSNConfig{Name: "orders.db"}))
+}
+
+func TestGivenDriverErrors_WhenIsUniqueViolation_ThenShouldMatchOnlyDuplicateKeys(t *testing.T) {
+	assert.True(t, MySQL.IsUniqueViolation(fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062})))
+	assert.False(t, MySQL.IsUniqueViolation(&mysql.MySQLError{Number: 1213}))
+	assert.True(t, Postgres.IsUniqueViolation(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, Postgres.IsUniqueViolation(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, SQLite.IsUniqueViolation(errors.New("UNIQUE constraint failed: orders.id")))
+	assert.False(t, SQLite.IsUniqueViolation(nil))
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/database/dialect"
+)
+
+const idempotencyColumns = "operation, idempotency_key, fingerprint, response, created_at, expires_at"
+
+// DefaultIdempotencyPurgeInterval is how often RunPurge deletes expired keys
+// unless configured.
+const DefaultIdempotencyPurgeInterval = time.Hour
+
+type IdempotencyRepository struct {
+	Db      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewIdempotencyRepository(db *sql.DB, d dialect.Dialect) *IdempotencyRepository {
+	return &IdempotencyRepository{Db: db, Dialect: d}
+}
+
+func (r *IdempotencyRepository) Find(ctx context.Context, operation, key string) (entity.IdempotencyRecord, error) {
+	var record entity.IdempotencyRecord
+	err := r.Db.QueryRowContext(ctx,
+		r.Dialect.Rebind("SELECT "+idempotencyColumns+" FROM idempotency_keys WHERE operation = ? AND idempotency_key = ? AND expires_at > ?"),
+		operation, key, time.Now().UTC(),
+	).Scan(&record.Operation, &record.Key, &record.Fingerprint, &record.Response, &record.CreatedAt, &record.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.IdempotencyRecord{}, entity.ErrIdempotencyRecordNotFound
+	}
+	return record, err
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.Db.ExecContext(ctx, r.Dialect.Rebind("DELETE FROM idempotency_keys WHERE expires_at <= ?"), now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RunPurge deletes expired keys every interval until ctx is cancelled. Expired
+// keys are already ignored by Find; purging only keeps the table small.
+func (r *IdempotencyRepository) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n, err := r.DeleteExpired(ctx, time.Now().UTC())
+		if err != nil && ctx.Err() == nil {
+			log.Printf("idempotency purge: %v", err)
+		}
+		if n > 0 {
+			log.Printf("idempotency purge: deleted %d expired keys", n)
+		}
+	}
+}
+
+// insertIdempotencyRecord relies on the primary key to let exactly one of
+// several concurrent requests with the same key through. An expired record is
+// replaced.
+func insertIdempotencyRecord(ctx context.Context, tx *sql.Tx, d dialect.Dialect, record entity.IdempotencyRecord) error {
+	_, err := tx.ExecContext(ctx,
+		d.Rebind("DELETE FROM idempotency_keys WHERE operation = ? AND idempotency_key = ? AND expires_at <= ?"),
+		record.Operation, record.Key, record.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		d.Rebind("INSERT INTO idempotency_keys ("+idempotencyColumns+") VALUES (?, ?, ?, ?, ?, ?)"),
+		record.Operation, record.Key, record.Fingerprint, record.Response, record.CreatedAt, record.ExpiresAt,
+	)
+	if d.IsUniqueViolation(err) {
+		return entity.ErrIdempotencyKeyTaken
+	}
+	return err
+}
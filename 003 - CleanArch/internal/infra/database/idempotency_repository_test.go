@@ -0,0 +1,191 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/event"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite
+	Database testDatabase
+	Db       *sql.DB
+}
+
+func (suite *IdempotencyRepositoryTestSuite) SetupTest() {
+	db, err := openTestDB(suite.Database)
+	suite.Require().NoError(err)
+	suite.Db = db
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TearDownTest() {
+	suite.Db.Close()
+}
+
+func TestIdempotencySuite(t *testing.T) {
+	forEachTestDatabase(t, func(t *testing.T, database testDatabase) {
+		suite.Run(t, &IdempotencyRepositoryTestSuite{Database: database})
+	})
+}
+
+func (suite *IdempotencyRepositoryTestSuite) repository() *IdempotencyRepository {
+	return NewIdempotencyRepository(suite.Db, suite.Database.Dialect)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) createOrderUseCase() *usecase.CreateOrderUseCase {
+	return usecase.NewCreateOrderUseCase(NewOrderRepository(suite.Db, suite.Database.Dialect), suite.repository(), event.NewOrderCreatedFactory(), events.NewEventDispatcher())
+}
+
+func (suite *IdempotencyRepositoryTestSuite) countOrders() int {
+	var count int
+	suite.NoError(suite.Db.QueryRow("SELECT COUNT(*) FROM orders").Scan(&count))
+	return count
+}
+
+func newOrderInput(id, key string) usecase.OrderInputDTO {
+	return usecase.OrderInputDTO{
+		ID:             id,
+		Currency:       "BRL",
+		Items:          []usecase.OrderItemInputDTO{{SKU: "SKU-1", Quantity: 2, UnitPrice: 1000, TaxRateBps: 1000}},
+		IdempotencyKey: key,
+	}
+}
+
+func (suite *IdempotencyRepositoryTestSuite) saveOrder(id string, record entity.IdempotencyRecord) error {
+	order, err := newTestOrder(id)
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	return NewOrderRepository(suite.Db, suite.Database.Dialect).SaveWithIdempotency(context.Background(), order, record)
+}
+
+func newCompletedRecord(operation, key, fingerprint string, ttl time.Duration) entity.IdempotencyRecord {
+	record := entity.NewIdempotencyRecord(operation, key, fingerprint, ttl)
+	record.Response = []byte(`{"id":"a"}`)
+	return record
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenASavedKey_WhenFind_ThenShouldReturnItForItsOperationOnly() {
+	ctx := context.Background()
+	suite.NoError(suite.saveOrder("a", newCompletedRecord("CreateOrder", "key-1", "fingerprint", time.Hour)))
+
+	stored, err := suite.repository().Find(ctx, "CreateOrder", "key-1")
+	suite.NoError(err)
+	suite.Equal("fingerprint", stored.Fingerprint)
+	suite.JSONEq(`{"id":"a"}`, string(stored.Response))
+
+	_, err = suite.repository().Find(ctx, "UpdateOrder", "key-1")
+	suite.ErrorIs(err, entity.ErrIdempotencyRecordNotFound)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenASavedKey_WhenSaveWithIdempotencyAgain_ThenShouldNotSaveTheOrder() {
+	suite.NoError(suite.saveOrder("a", newCompletedRecord("CreateOrder", "key-1", "fingerprint", time.Hour)))
+
+	err := suite.saveOrder("b", newCompletedRecord("CreateOrder", "key-1", "fingerprint", time.Hour))
+	suite.ErrorIs(err, entity.ErrIdempotencyKeyTaken)
+	suite.Equal(1, suite.countOrders())
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenAnExpiredKey_WhenSaveWithIdempotency_ThenShouldReplaceIt() {
+	ctx := context.Background()
+	suite.NoError(suite.saveOrder("a", newCompletedRecord("CreateOrder", "key-1", "old", -time.Minute)))
+	_, err := suite.repository().Find(ctx, "CreateOrder", "key-1")
+	suite.ErrorIs(err, entity.ErrIdempotencyRecordNotFound)
+
+	suite.NoError(suite.saveOrder("b", newCompletedRecord("CreateOrder", "key-1", "new", time.Hour)))
+	stored, err := suite.repository().Find(ctx, "CreateOrder", "key-1")
+	suite.NoError(err)
+	suite.Equal("new", stored.Fingerprint)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenExpiredKeys_WhenDeleteExpired_ThenShouldKeepTheOthers() {
+	ctx := context.Background()
+	suite.NoError(suite.saveOrder("a", newCompletedRecord("CreateOrder", "key-1", "fingerprint", -time.Minute)))
+	suite.NoError(suite.saveOrder("b", newCompletedRecord("CreateOrder", "key-2", "fingerprint", time.Hour)))
+
+	deleted, err := suite.repository().DeleteExpired(ctx, time.Now().UTC())
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+	_, err = suite.repository().Find(ctx, "CreateOrder", "key-2")
+	suite.NoError(err)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenARepeatedKey_WhenCreateOrder_ThenShouldReplayTheFirstResponse() {
+	createOrder := suite.createOrderUseCase()
+	first, err := createOrder.Execute(context.Background(), newOrderInput("", "key-1"))
+	suite.NoError(err)
+
+	second, err := createOrder.Execute(context.Background(), newOrderInput("", "key-1"))
+	suite.NoError(err)
+	suite.Equal(first.ID, second.ID)
+	suite.Equal(first.FinalPrice, second.FinalPrice)
+	suite.Equal(1, suite.countOrders())
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenConcurrentRequestsWithTheSameKey_WhenCreateOrder_ThenShouldCreateOneOrder() {
+	createOrder := suite.createOrderUseCase()
+	ids := make(chan string, 5)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(ids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := createOrder.Execute(context.Background(), newOrderInput("", "key-1"))
+			suite.NoError(err)
+			ids <- output.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	first := <-ids
+	for id := range ids {
+		suite.Equal(first, id)
+	}
+	suite.Equal(1, suite.countOrders())
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenAKeyReusedWithAnotherPayload_WhenCreateOrder_ThenShouldReject() {
+	createOrder := suite.createOrderUseCase()
+	_, err := createOrder.Execute(context.Background(), newOrderInput("a", "key-1"))
+	suite.NoError(err)
+
+	_, err = createOrder.Execute(context.Background(), newOrderInput("b", "key-1"))
+	suite.ErrorIs(err, entity.ErrIdempotencyKeyReused)
+	suite.Equal(1, suite.countOrders())
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenAFailedRequest_WhenRetriedWithTheSameKey_ThenShouldCreateTheOrder() {
+	createOrder := suite.createOrderUseCase()
+	_, err := createOrder.Execute(context.Background(), newOrderInput("a", ""))
+	suite.NoError(err)
+
+	_, err = createOrder.Execute(context.Background(), newOrderInput("a", "key-1"))
+	suite.ErrorIs(err, entity.ErrOrderAlreadyExists)
+
+	_, err = suite.Db.Exec("DELETE FROM order_items")
+	suite.NoError(err)
+	_, err = suite.Db.Exec("DELETE FROM orders")
+	suite.NoError(err)
+	_, err = createOrder.Execute(context.Background(), newOrderInput("a", "key-1"))
+	suite.NoError(err)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestGivenNoID_WhenCreateOrder_ThenShouldGenerateAULID() {
+	output, err := suite.createOrderUseCase().Execute(context.Background(), newOrderInput("", ""))
+	suite.NoError(err)
+	_, err = ulid.ParseStrict(output.ID)
+	suite.NoError(err)
+
+	order, err := NewOrderRepository(suite.Db, suite.Database.Dialect).FindByID(context.Background(), output.ID)
+	suite.NoError(err)
+	suite.Equal(output.ID, order.ID)
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/infra/database/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The old init.sql only ever ran on MySQL.
+func TestGivenTheLegacyMySQLSchema_WhenMigrated_ThenShouldKeepItsOrders(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+	db, err := sql.Open(dialect.MySQL.DriverName(), dsn)
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := migrations.NewMigrator(db, dialect.MySQL)
+	require.NoError(t, err)
+	_, err = migrator.Down(len(migrator.Migrations))
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE orders (
+		id varchar(255) DEFAULT NULL,
+		price float(10,2) DEFAULT NULL,
+		tax float(10,2) DEFAULT NULL,
+		final_price float(10,2) DEFAULT NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO orders (id, price, tax, final_price) VALUES ('legacy', 10.5, 1.05, 11.55)")
+	require.NoError(t, err)
+
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	repo := NewOrderRepository(db, dialect.MySQL)
+	order, err := repo.FindByID(context.Background(), "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "BRL", order.Currency)
+	assert.Equal(t, int64(1050), order.Price.Amount)
+	assert.Equal(t, int64(1155), order.FinalPrice.Amount)
+	assert.Equal(t, entity.OrderStatusPending, order.Status)
+	assert.Equal(t, int64(1), order.Version)
+
+	// created_at keeps the microseconds the list cursor compares
+	fresh, err := newTestOrder("fresh")
+	require.NoError(t, err)
+	require.NoError(t, fresh.CalculateFinalPrice())
+	fresh.CreatedAt = time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	require.NoError(t, repo.Save(context.Background(), fresh))
+	found, err := repo.FindByID(context.Background(), "fresh")
+	require.NoError(t, err)
+	assert.True(t, fresh.CreatedAt.Equal(found.CreatedAt), found.CreatedAt)
+}
@@ -0,0 +1,40 @@
+-- Upgrades the orders table created by the old internal/infra/sql/init.sql,
+-- with float prices and no primary key, to 0001_create_orders. Prices become
+-- minor units, the currency BRL and the status pending; rows without an id
+-- are dropped. Duplicate ids make the primary key, and the upgrade, fail.
+DELETE FROM orders WHERE id IS NULL;
+
+ALTER TABLE orders
+    ADD COLUMN price_minor bigint NULL,
+    ADD COLUMN tax_minor bigint NULL,
+    ADD COLUMN final_price_minor bigint NULL;
+
+UPDATE orders SET
+    price_minor = ROUND(COALESCE(price, 0) * 100),
+    tax_minor = ROUND(COALESCE(tax, 0) * 100),
+    final_price_minor = ROUND(COALESCE(final_price, 0) * 100);
+
+ALTER TABLE orders
+    DROP COLUMN price,
+    DROP COLUMN tax,
+    DROP COLUMN final_price;
+
+ALTER TABLE orders
+    MODIFY id varchar(255) NOT NULL,
+    ADD COLUMN currency char(3) NOT NULL DEFAULT 'BRL' AFTER id,
+    CHANGE price_minor price bigint NOT NULL,
+    CHANGE tax_minor tax bigint NOT NULL,
+    CHANGE final_price_minor final_price bigint NOT NULL,
+    ADD COLUMN status varchar(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN paid_at datetime NULL,
+    ADD COLUMN shipped_at datetime NULL,
+    ADD COLUMN delivered_at datetime NULL,
+    ADD COLUMN cancelled_at datetime NULL,
+    ADD COLUMN refunded_at datetime NULL,
+    ADD COLUMN version bigint NOT NULL DEFAULT 1,
+    ADD PRIMARY KEY (id),
+    ADD KEY idx_orders_created_at (created_at, id),
+    ADD KEY idx_orders_final_price (final_price, id);
+
+ALTER TABLE orders ALTER COLUMN currency DROP DEFAULT;
@@ -0,0 +1,250 @@
+// Package migrations holds the versioned database schema, embedded in the
+// binary, and applies it.
+//
+// Each dialect has its own directory of NNNN_name.up.sql and
+// NNNN_name.down.sql files. Applied versions are recorded in the
+// schema_migrations table. legacy/<dialect>.sql upgrades an orders table
+// created before the migrations, by the old init.sql, ahead of the first one.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"CleanArch/internal/infra/database/dialect"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite3/*.sql legacy/*.sql
+var files embed.FS
+
+var (
+	ErrUnknownDialect = errors.New("no migrations for dialect")
+	ErrLegacySchema   = errors.New("the orders table predates the migrations and there is no upgrade for this dialect")
+)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load returns the migrations for d ordered by version.
+func Load(d dialect.Dialect) ([]Migration, error) {
+	dir := string(d)
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDialect, d)
+	}
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction, ok := cutDirection(name)
+		if !ok {
+			return nil, fmt.Errorf("migrations: %s: want NNNN_name.up.sql or NNNN_name.down.sql", name)
+		}
+		number, title, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("migrations: %s: invalid version", name)
+		}
+		content, err := files.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: title}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migrations: version %d needs both an up and a down file", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func cutDirection(name string) (base, direction string, ok bool) {
+	if base, ok = strings.CutSuffix(name, ".up.sql"); ok {
+		return base, "up", true
+	}
+	if base, ok = strings.CutSuffix(name, ".down.sql"); ok {
+		return base, "down", true
+	}
+	return "", "", false
+}
+
+type Migrator struct {
+	Db         *sql.DB
+	Dialect    dialect.Dialect
+	Migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, d dialect.Dialect) (*Migrator, error) {
+	migrations, err := Load(d)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{Db: db, Dialect: d, Migrations: migrations}, nil
+}
+
+// Up applies every pending migration in order and returns the ones applied.
+// On a database with a legacy orders table it upgrades that table first.
+func (m *Migrator) Up() ([]Migration, error) {
+	current, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	if current == 0 {
+		if err := m.upgradeLegacy(); err != nil {
+			return nil, err
+		}
+	}
+	var applied []Migration
+	for _, migration := range m.Migrations {
+		if migration.Version <= current {
+			continue
+		}
+		if err := m.apply(migration, migration.Up, true); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns
+// the ones reverted.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	current, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	var reverted []Migration
+	for i := len(m.Migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := m.Migrations[i]
+		if migration.Version > current {
+			continue
+		}
+		if err := m.apply(migration, migration.Down, false); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// Version returns the latest applied version, 0 on an empty database.
+func (m *Migrator) Version() (int, error) {
+	if err := m.ensureTable(); err != nil {
+		return 0, err
+	}
+	var version sql.NullInt64
+	err := m.Db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+func (m *Migrator) ensureTable() error {
+	timestamp := "datetime"
+	if m.Dialect == dialect.Postgres {
+		timestamp = "timestamptz"
+	}
+	_, err := m.Db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint NOT NULL,
+		name varchar(255) NOT NULL,
+		applied_at ` + timestamp + ` NOT NULL,
+		PRIMARY KEY (version))`)
+	return err
+}
+
+// upgradeLegacy brings an orders table without a version column, as the old
+// init.sql created it, to the shape of the first migration, whose CREATE
+// TABLE IF NOT EXISTS would otherwise leave it as it is.
+func (m *Migrator) upgradeLegacy() error {
+	if _, err := m.Db.Exec("SELECT 1 FROM orders WHERE 1 = 0"); err != nil {
+		// no orders table
+		return nil
+	}
+	if _, err := m.Db.Exec("SELECT version FROM orders WHERE 1 = 0"); err == nil {
+		return nil
+	}
+	script, err := files.ReadFile("legacy/" + string(m.Dialect) + ".sql")
+	if err != nil {
+		return fmt.Errorf("migrations: %w: %s", ErrLegacySchema, m.Dialect)
+	}
+	// MySQL commits each ALTER, so a failure leaves the steps before it
+	for _, statement := range splitStatements(string(script)) {
+		if _, err := m.Db.Exec(statement); err != nil {
+			return fmt.Errorf("migrations: upgrading the legacy orders table: %w", err)
+		}
+	}
+	return nil
+}
+
+// apply runs script and records the result in one transaction. MySQL commits
+// DDL implicitly, so a failed script there may leave a partial change behind.
+func (m *Migrator) apply(migration Migration, script string, up bool) error {
+	tx, err := m.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("migrations: %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	if up {
+		_, err = tx.Exec(m.Dialect.Rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"),
+			migration.Version, migration.Name, time.Now().UTC())
+	} else {
+		_, err = tx.Exec(m.Dialect.Rebind("DELETE FROM schema_migrations WHERE version = ?"), migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// splitStatements splits a script on the semicolons that end a line, since
+// drivers run one statement per Exec by default. Comment lines are dropped,
+// so a script may hold only a comment when a dialect needs no change.
+func splitStatements(script string) []string {
+	var statements []string
+	for _, statement := range strings.Split(script, ";\n") {
+		var lines []string
+		for _, line := range strings.Split(statement, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+				lines = append(lines, line)
+			}
+		}
+		statement = strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";")
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
@@ -0,0 +1,120 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	"CleanArch/internal/infra/database/dialect"
+	"github.com/stretchr/testify/suite"
+
+	// sqlite
+	_ "modernc.org/sqlite"
+)
+
+type MigratorTestSuite struct {
+	suite.Suite
+	Db       *sql.DB
+	Migrator *Migrator
+}
+
+func (suite *MigratorTestSuite) SetupTest() {
+	db, err := sql.Open(dialect.SQLite.DriverName(), ":memory:")
+	suite.NoError(err)
+	db.SetMaxOpenConns(1)
+	suite.Db = db
+	suite.Migrator, err = NewMigrator(db, dialect.SQLite)
+	suite.NoError(err)
+}
+
+func (suite *MigratorTestSuite) TearDownTest() {
+	suite.Db.Close()
+}
+
+func TestMigratorSuite(t *testing.T) {
+	suite.Run(t, new(MigratorTestSuite))
+}
+
+func (suite *MigratorTestSuite) tableExists(name string) bool {
+	var count int
+	err := suite.Db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+	suite.NoError(err)
+	return count == 1
+}
+
+func (suite *MigratorTestSuite) TestGivenAnEmptyDatabase_WhenUp_ThenShouldApplyEveryMigrationOnce() {
+	applied, err := suite.Migrator.Up()
+	suite.NoError(err)
+	suite.Len(applied, len(suite.Migrator.Migrations))
+
+	version, err := suite.Migrator.Version()
+	suite.NoError(err)
+	suite.Equal(suite.Migrator.Migrations[len(suite.Migrator.Migrations)-1].Version, version)
+	for _, table := range []string{"orders", "order_items", "outbox", "processed_events", "order_summaries", "idempotency_keys"} {
+		suite.True(suite.tableExists(table), table)
+	}
+
+	applied, err = suite.Migrator.Up()
+	suite.NoError(err)
+	suite.Empty(applied)
+}
+
+func (suite *MigratorTestSuite) TestGivenAMigratedDatabase_WhenDown_ThenShouldRevertNewestFirst() {
+	_, err := suite.Migrator.Up()
+	suite.NoError(err)
+
+	reverted, err := suite.Migrator.Down(1)
+	suite.NoError(err)
+	suite.Len(reverted, 1)
+	suite.Equal(suite.Migrator.Migrations[len(suite.Migrator.Migrations)-1].Version, reverted[0].Version)
+	version, err := suite.Migrator.Version()
+	suite.NoError(err)
+	suite.Equal(suite.Migrator.Migrations[len(suite.Migrator.Migrations)-2].Version, version)
+	suite.True(suite.tableExists("orders"))
+
+	reverted, err = suite.Migrator.Down(len(suite.Migrator.Migrations))
+	suite.NoError(err)
+	suite.Len(reverted, len(suite.Migrator.Migrations)-1)
+	suite.False(suite.tableExists("orders"))
+
+	version, err = suite.Migrator.Version()
+	suite.NoError(err)
+	suite.Equal(0, version)
+}
+
+func (suite *MigratorTestSuite) TestGivenEveryDialect_WhenLoad_ThenShouldHaveTheSameVersions() {
+	sqlite, err := Load(dialect.SQLite)
+	suite.NoError(err)
+	for _, d := range []dialect.Dialect{dialect.MySQL, dialect.Postgres} {
+		migrations, err := Load(d)
+		suite.NoError(err)
+		suite.Equal(len(sqlite), len(migrations), d)
+		for i := range sqlite {
+			suite.Equal(sqlite[i].Version, migrations[i].Version, d)
+			suite.Equal(sqlite[i].Name, migrations[i].Name, d)
+		}
+	}
+
+	_, err = Load("oracle")
+	suite.ErrorIs(err, ErrUnknownDialect)
+}
+
+func (suite *MigratorTestSuite) TestGivenALegacyOrdersTable_WhenUpWithoutAnUpgrade_ThenShouldFailBeforeMigrating() {
+	_, err := suite.Db.Exec(`CREATE TABLE orders (id varchar(255), price float, tax float, final_price float)`)
+	suite.NoError(err)
+
+	applied, err := suite.Migrator.Up()
+
+	suite.ErrorIs(err, ErrLegacySchema)
+	suite.Empty(applied)
+	suite.False(suite.tableExists("order_items"))
+}
+
+func (suite *MigratorTestSuite) TestGivenTheMySQLLegacyUpgrade_WhenSplit_ThenShouldDropItsComments() {
+	script, err := files.ReadFile("legacy/mysql.sql")
+	suite.NoError(err)
+	statements := splitStatements(string(script))
+	suite.Equal("DELETE FROM orders WHERE id IS NULL", statements[0])
+	for _, statement := range statements {
+		suite.NotContains(statement, "--")
+	}
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id varchar(36) NOT NULL,
+    aggregate_id varchar(255) NOT NULL,
+    event_name varchar(255) NOT NULL,
+    payload json NOT NULL,
+    created_at datetime(6) NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    last_error text NOT NULL,
+    next_attempt_at datetime(6) NOT NULL,
+    published_at datetime(6) NULL,
+    PRIMARY KEY (id),
+    KEY idx_outbox_pending (published_at, next_attempt_at)
+);
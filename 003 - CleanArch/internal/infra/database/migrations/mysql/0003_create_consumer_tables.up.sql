@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id varchar(36) NOT NULL,
+    event_name varchar(255) NOT NULL,
+    processed_at datetime(6) NOT NULL,
+    PRIMARY KEY (event_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_summaries (
+    order_id varchar(255) NOT NULL,
+    status varchar(20) NOT NULL,
+    currency char(3) NOT NULL,
+    item_count bigint NOT NULL,
+    final_price bigint NOT NULL,
+    version bigint NOT NULL,
+    created_at datetime(6) NOT NULL,
+    updated_at datetime(6) NOT NULL,
+    PRIMARY KEY (order_id)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE orders
+    MODIFY created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    MODIFY paid_at datetime NULL,
+    MODIFY shipped_at datetime NULL,
+    MODIFY delivered_at datetime NULL,
+    MODIFY cancelled_at datetime NULL,
+    MODIFY refunded_at datetime NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE orders
+    MODIFY created_at datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    MODIFY paid_at datetime(6) NULL,
+    MODIFY shipped_at datetime(6) NULL,
+    MODIFY delivered_at datetime(6) NULL,
+    MODIFY cancelled_at datetime(6) NULL,
+    MODIFY refunded_at datetime(6) NULL;
@@ -0,0 +1 @@
+ALTER TABLE outbox ADD COLUMN dead_at datetime(6) NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id varchar(36) NOT NULL,
+    aggregate_id varchar(255) NOT NULL,
+    event_name varchar(255) NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamptz NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    last_error text NOT NULL,
+    next_attempt_at timestamptz NOT NULL,
+    published_at timestamptz NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (published_at, next_attempt_at);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    operation varchar(64) NOT NULL,
+    idempotency_key varchar(255) NOT NULL,
+    fingerprint char(64) NOT NULL,
+    response jsonb NULL,
+    created_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL,
+    PRIMARY KEY (operation, idempotency_key)
+);
@@ -0,0 +1 @@
+-- timestamptz already keeps microseconds; the change is MySQL's datetime(6).
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id varchar(255) NOT NULL,
+    currency char(3) NOT NULL,
+    price bigint NOT NULL,
+    tax bigint NOT NULL,
+    final_price bigint NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paid_at datetime NULL,
+    shipped_at datetime NULL,
+    delivered_at datetime NULL,
+    cancelled_at datetime NULL,
+    refunded_at datetime NULL,
+    version bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders (created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_orders_final_price ON orders (final_price, id);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id varchar(255) NOT NULL,
+    line_number int NOT NULL,
+    sku varchar(255) NOT NULL,
+    quantity bigint NOT NULL,
+    unit_price bigint NOT NULL,
+    tax_rate_bps bigint NOT NULL,
+    PRIMARY KEY (order_id, line_number)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS order_summaries;
+DROP TABLE IF EXISTS processed_events;
@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN created_by varchar(255) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+-- SQLite keeps timestamps as given; the change is MySQL's datetime(6).
@@ -1,60 +1,358 @@
 package database
 
 import (
+	"context"
 	"database/sql"
-  "fmt"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/database/dialect"
 )
 
+const orderColumns = "id, currency, price, tax, final_price, status, created_at, paid_at, shipped_at, delivered_at, cancelled_at, refunded_at, version, created_by"
+
+// OrderRepository writes queries with ? placeholders and rebinds them for
+// Dialect.
 type OrderRepository struct {
-	Db *sql.DB
+	Db      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewOrderRepository(db *sql.DB, d dialect.Dialect) *OrderRepository {
+	return &OrderRepository{Db: db, Dialect: d}
+}
+
+func (r *OrderRepository) Save(ctx context.Context, order *entity.Order) error {
+	return r.SaveWithOutbox(ctx, order)
+}
+
+func (r *OrderRepository) SaveWithOutbox(ctx context.Context, order *entity.Order, messages ...entity.OutboxMessage) error {
+	return r.save(ctx, order, nil, messages)
 }
 
-func NewOrderRepository(db *sql.DB) *OrderRepository {
-	return &OrderRepository{Db: db}
+// SaveWithIdempotency inserts record before the order, so when two requests
+// with the same key race the second fails on the key and saves nothing.
+func (r *OrderRepository) SaveWithIdempotency(ctx context.Context, order *entity.Order, record entity.IdempotencyRecord, messages ...entity.OutboxMessage) error {
+	return r.save(ctx, order, &record, messages)
+}
+
+func (r *OrderRepository) save(ctx context.Context, order *entity.Order, record *entity.IdempotencyRecord, messages []entity.OutboxMessage) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if record != nil {
+		if err := insertIdempotencyRecord(ctx, tx, r.Dialect, *record); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx,
+		r.Dialect.Rebind("INSERT INTO orders ("+orderColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+		order.ID, order.Currency, order.Price.Amount, order.Tax.Amount, order.FinalPrice.Amount,
+		order.Status, order.CreatedAt,
+		order.PaidAt, order.ShippedAt, order.DeliveredAt, order.CancelledAt, order.RefundedAt,
+		order.Version, order.CreatedBy,
+	)
+	if r.Dialect.IsUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", entity.ErrOrderAlreadyExists, order.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := insertOrderItems(ctx, tx, r.Dialect, order); err != nil {
+		return err
+	}
+	if err := insertOutboxMessages(ctx, tx, r.Dialect, messages); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *OrderRepository) Save(order *entity.Order) error {
-	stmt, err := r.Db.Prepare("INSERT INTO orders (id, price, tax, final_price) VALUES (?, ?, ?, ?)")
+func (r *OrderRepository) Update(ctx context.Context, order *entity.Order, messages ...entity.OutboxMessage) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	_, err = stmt.Exec(order.ID, order.Price, order.Tax, order.FinalPrice)
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		r.Dialect.Rebind("UPDATE orders SET currency = ?, price = ?, tax = ?, final_price = ?, status = ?, paid_at = ?, shipped_at = ?, delivered_at = ?, cancelled_at = ?, refunded_at = ?, version = version + 1 WHERE id = ? AND version = ?"),
+		order.Currency, order.Price.Amount, order.Tax.Amount, order.FinalPrice.Amount, order.Status,
+		order.PaidAt, order.ShippedAt, order.DeliveredAt, order.CancelledAt, order.RefundedAt,
+		order.ID, order.Version,
+	)
 	if err != nil {
 		return err
 	}
+	if err := checkVersionedWrite(ctx, tx, r.Dialect, result, order.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, r.Dialect.Rebind("DELETE FROM order_items WHERE order_id = ?"), order.ID); err != nil {
+		return err
+	}
+	if err := insertOrderItems(ctx, tx, r.Dialect, order); err != nil {
+		return err
+	}
+	if err := insertOutboxMessages(ctx, tx, r.Dialect, messages); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	order.Version++
 	return nil
 }
 
-func (r *OrderRepository) GetTotal() (int, error) {
-	var total int
-	err := r.Db.QueryRow("Select count(*) from orders").Scan(&total)
+func (r *OrderRepository) Delete(ctx context.Context, id string, version int64, messages ...entity.OutboxMessage) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, r.Dialect.Rebind("DELETE FROM orders WHERE id = ? AND version = ?"), id, version)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	return total, nil
+	if err := checkVersionedWrite(ctx, tx, r.Dialect, result, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, r.Dialect.Rebind("DELETE FROM order_items WHERE order_id = ?"), id); err != nil {
+		return err
+	}
+	if err := insertOutboxMessages(ctx, tx, r.Dialect, messages); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *OrderRepository) List() ([]entity.Order, error) {
-	rows, err := r.Db.Query("select id, price, tax, final_price from orders")
+// checkVersionedWrite tells a missing order apart from a stale version when a
+// versioned UPDATE or DELETE touched no rows.
+func checkVersionedWrite(ctx context.Context, tx *sql.Tx, d dialect.Dialect, result sql.Result, id string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	var exists int
+	err = tx.QueryRowContext(ctx, d.Rebind("SELECT 1 FROM orders WHERE id = ?"), id).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return entity.ErrVersionConflict
+}
+
+func (r *OrderRepository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
+	row := r.Db.QueryRowContext(ctx, r.Dialect.Rebind("SELECT "+orderColumns+" FROM orders WHERE id = ?"), id)
+	order, err := scanOrder(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	orders := []*entity.Order{order}
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// FindByIDs loads the orders with the given IDs in one round trip; IDs
+// without an order are left out.
+func (r *OrderRepository) FindByIDs(ctx context.Context, ids []string) ([]entity.Order, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	return r.queryOrders(ctx, "SELECT "+orderColumns+" FROM orders WHERE id IN ("+placeholders+")", args)
+}
+
+func (r *OrderRepository) List(ctx context.Context, query entity.OrderListQuery) ([]entity.Order, error) {
+	sqlQuery, args := buildListQuery(query)
+	return r.queryOrders(ctx, sqlQuery, args)
+}
+
+// queryOrders runs a select of orderColumns and loads the items of the
+// orders found.
+func (r *OrderRepository) queryOrders(ctx context.Context, sqlQuery string, args []any) ([]entity.Order, error) {
+	rows, err := r.Db.QueryContext(ctx, r.Dialect.Rebind(sqlQuery), args...)
 	if err != nil {
-		fmt.Printf("list error database: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var orders []entity.Order
+	var loaded []*entity.Order
 	for rows.Next() {
-		var o entity.Order
-		err = rows.Scan(&o.ID, &o.Price, &o.Tax, &o.FinalPrice)
+		o, err := scanOrder(rows)
 		if err != nil {
-			fmt.Printf("scan rows error database: %v", err)
 			return nil, err
 		}
-		orders = append(orders, o)
+		loaded = append(loaded, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(ctx, loaded); err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("orders: %v", orders)
+	orders := make([]entity.Order, 0, len(loaded))
+	for _, o := range loaded {
+		orders = append(orders, *o)
+	}
 
 	return orders, nil
-}
\ No newline at end of file
+}
+
+// buildListQuery renders a keyset-paginated select. Column names come from
+// entity.OrderSortField values only, never from user input.
+func buildListQuery(query entity.OrderListQuery) (string, []any) {
+	var where []string
+	var args []any
+
+	filter := query.Filter
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.MinPrice != nil {
+		where = append(where, "final_price >= ?")
+		args = append(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		where = append(where, "final_price <= ?")
+		args = append(args, *filter.MaxPrice)
+	}
+	if filter.CreatedFrom != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		where = append(where, "created_at <= ?")
+		args = append(args, *filter.CreatedTo)
+	}
+
+	column := string(entity.OrderSortByCreatedAt)
+	if query.SortBy == entity.OrderSortByFinalPrice {
+		column = string(entity.OrderSortByFinalPrice)
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if query.After != nil {
+		var value any = query.After.CreatedAt
+		if column == string(entity.OrderSortByFinalPrice) {
+			value = query.After.FinalPrice
+		}
+		where = append(where, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, comparison))
+		args = append(args, value, value, query.After.ID)
+	}
+
+	sqlQuery := "SELECT " + orderColumns + " FROM orders"
+	if len(where) > 0 {
+		sqlQuery += " WHERE " + strings.Join(where, " AND ")
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY %[1]s %[2]s, id %[2]s LIMIT ?", column, direction)
+	args = append(args, query.Limit)
+
+	return sqlQuery, args
+}
+
+func insertOrderItems(ctx context.Context, tx *sql.Tx, d dialect.Dialect, order *entity.Order) error {
+	stmt, err := tx.PrepareContext(ctx, d.Rebind("INSERT INTO order_items (order_id, line_number, sku, quantity, unit_price, tax_rate_bps) VALUES (?, ?, ?, ?, ?, ?)"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for i, item := range order.Items {
+		_, err = stmt.ExecContext(ctx, order.ID, i, item.SKU, item.Quantity, item.UnitPrice.Amount, item.TaxRateBps)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadItems fetches the line items of all given orders with a single query.
+func (r *OrderRepository) loadItems(ctx context.Context, orders []*entity.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byID := make(map[string]*entity.Order, len(orders))
+	args := make([]any, 0, len(orders))
+	for _, o := range orders {
+		byID[o.ID] = o
+		args = append(args, o.ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	rows, err := r.Db.QueryContext(ctx,
+		r.Dialect.Rebind("SELECT order_id, sku, quantity, unit_price, tax_rate_bps FROM order_items WHERE order_id IN ("+placeholders+") ORDER BY order_id, line_number"),
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var item entity.OrderItem
+		err = rows.Scan(&orderID, &item.SKU, &item.Quantity, &item.UnitPrice.Amount, &item.TaxRateBps)
+		if err != nil {
+			return err
+		}
+		order := byID[orderID]
+		item.UnitPrice.Currency = order.Currency
+		order.Items = append(order.Items, item)
+	}
+	return rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOrder(s scanner) (*entity.Order, error) {
+	var o entity.Order
+	var paidAt, shippedAt, deliveredAt, cancelledAt, refundedAt sql.NullTime
+	err := s.Scan(
+		&o.ID, &o.Currency, &o.Price.Amount, &o.Tax.Amount, &o.FinalPrice.Amount, &o.Status, &o.CreatedAt,
+		&paidAt, &shippedAt, &deliveredAt, &cancelledAt, &refundedAt, &o.Version, &o.CreatedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	o.Price.Currency = o.Currency
+	o.Tax.Currency = o.Currency
+	o.FinalPrice.Currency = o.Currency
+	o.PaidAt = nullTimePtr(paidAt)
+	o.ShippedAt = nullTimePtr(shippedAt)
+	o.DeliveredAt = nullTimePtr(deliveredAt)
+	o.CancelledAt = nullTimePtr(cancelledAt)
+	o.RefundedAt = nullTimePtr(refundedAt)
+	return &o, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
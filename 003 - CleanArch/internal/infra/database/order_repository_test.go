@@ -1,51 +1,315 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"CleanArch/internal/entity"
 	"github.com/stretchr/testify/suite"
-
-	// sqlite3
-	_ "github.com/mattn/go-sqlite3"
 )
 
+// OrderRepositoryTestSuite is the repository contract; it runs against
+// every database returned by testDatabases.
 type OrderRepositoryTestSuite struct {
 	suite.Suite
-	Db *sql.DB
+	Database testDatabase
+	Db       *sql.DB
 }
 
-func (suite *OrderRepositoryTestSuite) SetupSuite() {
-	db, err := sql.Open("sqlite3", ":memory:")
-	suite.NoError(err)
-	db.Exec("CREATE TABLE orders (id varchar(255) NOT NULL, price float NOT NULL, tax float NOT NULL, final_price float NOT NULL, PRIMARY KEY (id))")
+func (suite *OrderRepositoryTestSuite) SetupTest() {
+	db, err := openTestDB(suite.Database)
+	suite.Require().NoError(err)
 	suite.Db = db
 }
 
+func (suite *OrderRepositoryTestSuite) repository() *OrderRepository {
+	return NewOrderRepository(suite.Db, suite.Database.Dialect)
+}
+
+// query runs a ? placeholder query in the suite's dialect.
+func (suite *OrderRepositoryTestSuite) query(query string, args ...any) *sql.Row {
+	return suite.Db.QueryRow(suite.Database.Dialect.Rebind(query), args...)
+}
+
 func (suite *OrderRepositoryTestSuite) TearDownTest() {
 	suite.Db.Close()
 }
 
+func newTestOrder(id string) (*entity.Order, error) {
+	items := []entity.OrderItem{
+		{SKU: "SKU-1", Quantity: 2, UnitPrice: entity.Money{Amount: 1050, Currency: "BRL"}, TaxRateBps: 1000},
+		{SKU: "SKU-2", Quantity: 1, UnitPrice: entity.Money{Amount: 199, Currency: "BRL"}, TaxRateBps: 0},
+	}
+	return entity.NewOrder(id, "BRL", items)
+}
+
 func TestSuite(t *testing.T) {
-	suite.Run(t, new(OrderRepositoryTestSuite))
+	forEachTestDatabase(t, func(t *testing.T, database testDatabase) {
+		suite.Run(t, &OrderRepositoryTestSuite{Database: database})
+	})
 }
 
 func (suite *OrderRepositoryTestSuite) TestGivenAnOrder_WhenSave_ThenShouldSaveOrder() {
-	order, err := entity.NewOrder("123", 10.0, 2.0)
+	order, err := newTestOrder("123")
 	suite.NoError(err)
 	suite.NoError(order.CalculateFinalPrice())
-	repo := NewOrderRepository(suite.Db)
-	err = repo.Save(order)
+	repo := suite.repository()
+	err = repo.Save(context.Background(), order)
 	suite.NoError(err)
 
 	var orderResult entity.Order
-	err = suite.Db.QueryRow("Select id, price, tax, final_price from orders where id = ?", order.ID).
-		Scan(&orderResult.ID, &orderResult.Price, &orderResult.Tax, &orderResult.FinalPrice)
+	err = suite.query("Select id, currency, price, tax, final_price, status from orders where id = ?", order.ID).
+		Scan(&orderResult.ID, &orderResult.Currency, &orderResult.Price.Amount, &orderResult.Tax.Amount, &orderResult.FinalPrice.Amount, &orderResult.Status)
 
 	suite.NoError(err)
 	suite.Equal(order.ID, orderResult.ID)
-	suite.Equal(order.Price, orderResult.Price)
-	suite.Equal(order.Tax, orderResult.Tax)
-	suite.Equal(order.FinalPrice, orderResult.FinalPrice)
+	suite.Equal(order.Currency, orderResult.Currency)
+	suite.Equal(order.Price.Amount, orderResult.Price.Amount)
+	suite.Equal(order.Tax.Amount, orderResult.Tax.Amount)
+	suite.Equal(order.FinalPrice.Amount, orderResult.FinalPrice.Amount)
+	suite.Equal(entity.OrderStatusPending, orderResult.Status)
+
+	var items int
+	suite.NoError(suite.query("Select count(*) from order_items where order_id = ?", order.ID).Scan(&items))
+	suite.Equal(2, items)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenASavedOrder_WhenFindByID_ThenShouldReturnOrder() {
+	order, err := newTestOrder("123")
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	repo := suite.repository()
+	suite.NoError(repo.Save(context.Background(), order))
+
+	found, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	suite.Equal(order.ID, found.ID)
+	suite.Equal(order.Price, found.Price)
+	suite.Equal(order.Tax, found.Tax)
+	suite.Equal(order.FinalPrice, found.FinalPrice)
+	suite.Equal(order.Items, found.Items)
+	suite.Equal(entity.OrderStatusPending, found.Status)
+	// databases keep micro- or whole seconds only
+	suite.WithinDuration(order.CreatedAt, found.CreatedAt, time.Second)
+	suite.Nil(found.PaidAt)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAnOrderWithCreator_WhenFindByID_ThenShouldReturnCreatedBy() {
+	order, err := newTestOrder("123")
+	suite.NoError(err)
+	order.CreatedBy = "user-1"
+	suite.NoError(order.CalculateFinalPrice())
+	repo := suite.repository()
+	suite.NoError(repo.Save(context.Background(), order))
+
+	found, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	suite.Equal("user-1", found.CreatedBy)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAnUnknownID_WhenFindByID_ThenShouldReturnNotFound() {
+	repo := suite.repository()
+	_, err := repo.FindByID(context.Background(), "missing")
+	suite.ErrorIs(err, entity.ErrOrderNotFound)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenSavedOrders_WhenFindByIDs_ThenShouldReturnTheOnesFound() {
+	repo := suite.repository()
+	for _, id := range []string{"1", "2", "3"} {
+		order, err := newTestOrder(id)
+		suite.NoError(err)
+		suite.NoError(order.CalculateFinalPrice())
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	found, err := repo.FindByIDs(context.Background(), []string{"3", "missing", "1"})
+	suite.NoError(err)
+	suite.Len(found, 2)
+	ids := []string{found[0].ID, found[1].ID}
+	suite.ElementsMatch([]string{"1", "3"}, ids)
+	suite.Len(found[0].Items, 2)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAPaidOrder_WhenUpdate_ThenShouldPersistStatus() {
+	order, err := newTestOrder("123")
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	repo := suite.repository()
+	suite.NoError(repo.Save(context.Background(), order))
+
+	suite.NoError(order.Pay())
+	suite.NoError(repo.Update(context.Background(), order))
+	suite.Equal(int64(2), order.Version)
+
+	found, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	suite.Equal(int64(2), found.Version)
+	suite.Equal(entity.OrderStatusPaid, found.Status)
+	suite.NotNil(found.PaidAt)
+	suite.WithinDuration(*order.PaidAt, *found.PaidAt, time.Second)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAnUnknownOrder_WhenUpdate_ThenShouldReturnNotFound() {
+	order, err := newTestOrder("missing")
+	suite.NoError(err)
+	repo := suite.repository()
+	suite.ErrorIs(repo.Update(context.Background(), order), entity.ErrOrderNotFound)
+}
+
+func (suite *OrderRepositoryTestSuite) saveOrders(ids ...string) []*entity.Order {
+	repo := suite.repository()
+	base := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	var orders []*entity.Order
+	for i, id := range ids {
+		order, err := newTestOrder(id)
+		suite.NoError(err)
+		order.Items[0].Quantity = int64(len(ids) - i)
+		suite.NoError(order.CalculateFinalPrice())
+		order.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		suite.NoError(repo.Save(context.Background(), order))
+		orders = append(orders, order)
+	}
+	return orders
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenManyOrders_WhenListWithCursor_ThenShouldReturnNextPage() {
+	orders := suite.saveOrders("a", "b", "c", "d", "e")
+	repo := suite.repository()
+
+	page, err := repo.List(context.Background(), entity.OrderListQuery{SortBy: entity.OrderSortByCreatedAt, Limit: 2})
+	suite.NoError(err)
+	suite.Len(page, 2)
+	suite.Equal("a", page[0].ID)
+	suite.Equal("b", page[1].ID)
+	suite.Len(page[0].Items, 2)
+
+	last := page[1]
+	page, err = repo.List(context.Background(), entity.OrderListQuery{
+		SortBy: entity.OrderSortByCreatedAt,
+		After:  &entity.OrderCursor{CreatedAt: last.CreatedAt, FinalPrice: last.FinalPrice.Amount, ID: last.ID},
+		Limit:  2,
+	})
+	suite.NoError(err)
+	suite.Len(page, 2)
+	suite.Equal(orders[2].ID, page[0].ID)
+	suite.Equal(orders[3].ID, page[1].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenManyOrders_WhenListSortedByPriceDesc_ThenShouldReturnMostExpensiveFirst() {
+	suite.saveOrders("a", "b", "c")
+	repo := suite.repository()
+
+	page, err := repo.List(context.Background(), entity.OrderListQuery{SortBy: entity.OrderSortByFinalPrice, Descending: true, Limit: 10})
+	suite.NoError(err)
+	suite.Len(page, 3)
+	suite.Equal("a", page[0].ID)
+	suite.Equal("c", page[2].ID)
+
+	last := page[0]
+	page, err = repo.List(context.Background(), entity.OrderListQuery{
+		SortBy:     entity.OrderSortByFinalPrice,
+		Descending: true,
+		After:      &entity.OrderCursor{CreatedAt: last.CreatedAt, FinalPrice: last.FinalPrice.Amount, ID: last.ID},
+		Limit:      10,
+	})
+	suite.NoError(err)
+	suite.Len(page, 2)
+	suite.Equal("b", page[0].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenFilters_WhenList_ThenShouldReturnOnlyMatchingOrders() {
+	orders := suite.saveOrders("a", "b", "c", "d")
+	repo := suite.repository()
+	suite.NoError(orders[1].Pay())
+	suite.NoError(repo.Update(context.Background(), orders[1]))
+
+	page, err := repo.List(context.Background(), entity.OrderListQuery{
+		SortBy: entity.OrderSortByCreatedAt,
+		Filter: entity.OrderListFilter{Status: entity.OrderStatusPaid},
+		Limit:  10,
+	})
+	suite.NoError(err)
+	suite.Len(page, 1)
+	suite.Equal("b", page[0].ID)
+
+	maxPrice := orders[2].FinalPrice.Amount
+	createdFrom := orders[1].CreatedAt
+	page, err = repo.List(context.Background(), entity.OrderListQuery{
+		SortBy: entity.OrderSortByCreatedAt,
+		Filter: entity.OrderListFilter{MaxPrice: &maxPrice, CreatedFrom: &createdFrom},
+		Limit:  10,
+	})
+	suite.NoError(err)
+	suite.Len(page, 2)
+	suite.Equal("c", page[0].ID)
+	suite.Equal("d", page[1].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAStaleVersion_WhenUpdate_ThenShouldReturnConflict() {
+	order, err := newTestOrder("123")
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	repo := suite.repository()
+	suite.NoError(repo.Save(context.Background(), order))
+
+	first, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	second, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+
+	first.Items = first.Items[:1]
+	suite.NoError(first.CalculateFinalPrice())
+	suite.NoError(repo.Update(context.Background(), first))
+
+	suite.NoError(second.Pay())
+	suite.ErrorIs(repo.Update(context.Background(), second), entity.ErrVersionConflict)
+
+	found, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	suite.Len(found.Items, 1)
+	suite.Equal(entity.OrderStatusPending, found.Status)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenASavedOrder_WhenDelete_ThenShouldRemoveOrderAndItems() {
+	order, err := newTestOrder("123")
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	repo := suite.repository()
+	suite.NoError(repo.Save(context.Background(), order))
+
+	suite.ErrorIs(repo.Delete(context.Background(), "123", 7), entity.ErrVersionConflict)
+	suite.ErrorIs(repo.Delete(context.Background(), "missing", 1), entity.ErrOrderNotFound)
+	suite.NoError(repo.Delete(context.Background(), "123", order.Version))
+
+	_, err = repo.FindByID(context.Background(), "123")
+	suite.ErrorIs(err, entity.ErrOrderNotFound)
+	var items int
+	suite.NoError(suite.query("Select count(*) from order_items where order_id = ?", "123").Scan(&items))
+	suite.Equal(0, items)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenACanceledContext_WhenSave_ThenShouldNotSaveOrder() {
+	order, err := newTestOrder("123")
+	suite.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.ErrorIs(suite.repository().Save(ctx, order), context.Canceled)
+
+	_, err = suite.repository().FindByID(context.Background(), "123")
+	suite.ErrorIs(err, entity.ErrOrderNotFound)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenABusyPool_WhenFindByIDWithDeadline_ThenShouldReturnDeadlineExceeded() {
+	suite.Db.SetMaxOpenConns(1)
+	tx, err := suite.Db.Begin()
+	suite.Require().NoError(err)
+	defer tx.Rollback()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = suite.repository().FindByID(ctx, "123")
+	suite.ErrorIs(err, context.DeadlineExceeded)
 }
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/database/dialect"
+)
+
+const outboxColumns = "id, aggregate_id, event_name, payload, created_at, attempts, last_error, next_attempt_at, published_at, dead_at"
+
+type OutboxRepository struct {
+	Db      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewOutboxRepository(db *sql.DB, d dialect.Dialect) *OutboxRepository {
+	return &OutboxRepository{Db: db, Dialect: d}
+}
+
+// FetchPending does not lock the rows it returns, so running more than one
+// relay against the same table may publish a message twice. That is within
+// the at-least-once contract consumers already have to handle.
+func (r *OutboxRepository) FetchPending(ctx context.Context, now time.Time, limit int) ([]entity.OutboxMessage, error) {
+	rows, err := r.Db.QueryContext(ctx,
+		r.Dialect.Rebind("SELECT "+outboxColumns+" FROM outbox WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ? ORDER BY created_at, id LIMIT ?"),
+		now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []entity.OutboxMessage
+	for rows.Next() {
+		var m entity.OutboxMessage
+		var publishedAt, deadAt sql.NullTime
+		err = rows.Scan(&m.ID, &m.AggregateID, &m.EventName, &m.Payload, &m.CreatedAt, &m.Attempts, &m.LastError, &m.NextAttemptAt, &publishedAt, &deadAt)
+		if err != nil {
+			return nil, err
+		}
+		m.PublishedAt = nullTimePtr(publishedAt)
+		m.DeadAt = nullTimePtr(deadAt)
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	_, err := r.Db.ExecContext(ctx, r.Dialect.Rebind("UPDATE outbox SET published_at = ?, attempts = attempts + 1, last_error = '' WHERE id = ?"), at, id)
+	return err
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id string, reason string, nextAttemptAt time.Time) error {
+	_, err := r.Db.ExecContext(ctx, r.Dialect.Rebind("UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?"), reason, nextAttemptAt, id)
+	return err
+}
+
+func (r *OutboxRepository) MarkDead(ctx context.Context, id string, reason string, at time.Time) error {
+	_, err := r.Db.ExecContext(ctx, r.Dialect.Rebind("UPDATE outbox SET attempts = attempts + 1, last_error = ?, dead_at = ? WHERE id = ?"), reason, at, id)
+	return err
+}
+
+func (r *OutboxRepository) Stats(ctx context.Context) (entity.OutboxStats, error) {
+	var stats entity.OutboxStats
+	var pending, failing, published, dead sql.NullInt64
+	err := r.Db.QueryRowContext(ctx, `SELECT
+		COUNT(CASE WHEN published_at IS NULL AND dead_at IS NULL THEN 1 END),
+		COUNT(CASE WHEN published_at IS NULL AND dead_at IS NULL AND attempts > 0 THEN 1 END),
+		COUNT(published_at),
+		COUNT(dead_at)
+		FROM outbox`).Scan(&pending, &failing, &published, &dead)
+	if err != nil {
+		return stats, err
+	}
+	stats.Pending = pending.Int64
+	stats.Failing = failing.Int64
+	stats.Published = published.Int64
+	stats.Dead = dead.Int64
+
+	var oldestAt sql.NullTime
+	err = r.Db.QueryRowContext(ctx, "SELECT created_at FROM outbox WHERE published_at IS NULL AND dead_at IS NULL ORDER BY created_at LIMIT 1").Scan(&oldestAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return stats, err
+	}
+	stats.OldestPendingAt = nullTimePtr(oldestAt)
+	return stats, nil
+}
+
+func insertOutboxMessages(ctx context.Context, tx *sql.Tx, d dialect.Dialect, messages []entity.OutboxMessage) error {
+	for _, m := range messages {
+		_, err := tx.ExecContext(ctx,
+			d.Rebind("INSERT INTO outbox ("+outboxColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+			m.ID, m.AggregateID, m.EventName, m.Payload, m.CreatedAt, m.Attempts, m.LastError, m.NextAttemptAt, m.PublishedAt, m.DeadAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/event"
+	"CleanArch/internal/infra/outbox"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxRepositoryTestSuite struct {
+	suite.Suite
+	Database testDatabase
+	Db       *sql.DB
+}
+
+func (suite *OutboxRepositoryTestSuite) SetupTest() {
+	db, err := openTestDB(suite.Database)
+	suite.Require().NoError(err)
+	suite.Db = db
+}
+
+func (suite *OutboxRepositoryTestSuite) TearDownTest() {
+	suite.Db.Close()
+}
+
+func TestOutboxSuite(t *testing.T) {
+	forEachTestDatabase(t, func(t *testing.T, database testDatabase) {
+		suite.Run(t, &OutboxRepositoryTestSuite{Database: database})
+	})
+}
+
+func (suite *OutboxRepositoryTestSuite) saveOrderWithMessage(id string) entity.OutboxMessage {
+	order, err := newTestOrder(id)
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	message := entity.NewOutboxMessage("msg-"+id, id, "OrderCreated", []byte(`{"id":"`+id+`"}`))
+	suite.NoError(NewOrderRepository(suite.Db, suite.Database.Dialect).SaveWithOutbox(context.Background(), order, message))
+	return message
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenAnOrder_WhenSaveWithOutbox_ThenShouldStoreMessage() {
+	message := suite.saveOrderWithMessage("a")
+
+	pending, err := NewOutboxRepository(suite.Db, suite.Database.Dialect).FetchPending(context.Background(), time.Now().UTC(), 10)
+	suite.NoError(err)
+	suite.Len(pending, 1)
+	suite.Equal(message.ID, pending[0].ID)
+	suite.Equal("a", pending[0].AggregateID)
+	suite.Equal("OrderCreated", pending[0].EventName)
+	suite.JSONEq(`{"id":"a"}`, string(pending[0].Payload))
+	suite.Nil(pending[0].PublishedAt)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenAFailingOutboxInsert_WhenSaveWithOutbox_ThenShouldNotSaveOrder() {
+	suite.saveOrderWithMessage("a")
+	order, err := newTestOrder("b")
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+
+	// reusing the message ID violates the outbox primary key
+	duplicate := entity.NewOutboxMessage("msg-a", "b", "OrderCreated", []byte(`{}`))
+	err = NewOrderRepository(suite.Db, suite.Database.Dialect).SaveWithOutbox(context.Background(), order, duplicate)
+	suite.Error(err)
+
+	_, err = NewOrderRepository(suite.Db, suite.Database.Dialect).FindByID(context.Background(), "b")
+	suite.True(errors.Is(err, entity.ErrOrderNotFound))
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenAnOrder_WhenUpdateAndDeleteWithOutbox_ThenShouldStoreTheirMessages() {
+	suite.saveOrderWithMessage("a")
+	repo := NewOrderRepository(suite.Db, suite.Database.Dialect)
+	order, err := repo.FindByID(context.Background(), "a")
+	suite.NoError(err)
+	suite.NoError(order.Pay())
+
+	paid := entity.NewOutboxMessage("msg-paid", "a", "OrderPaid", []byte(`{}`))
+	suite.NoError(repo.Update(context.Background(), order, paid))
+	// a conflicting write stores neither the change nor its message
+	stale := entity.NewOutboxMessage("msg-stale", "a", "OrderDeleted", []byte(`{}`))
+	suite.ErrorIs(repo.Delete(context.Background(), "a", 1, stale), entity.ErrVersionConflict)
+	deleted := entity.NewOutboxMessage("msg-deleted", "a", "OrderDeleted", []byte(`{}`))
+	suite.NoError(repo.Delete(context.Background(), "a", order.Version, deleted))
+
+	pending, err := NewOutboxRepository(suite.Db, suite.Database.Dialect).FetchPending(context.Background(), time.Now().UTC(), 10)
+	suite.NoError(err)
+	var names []string
+	for _, m := range pending {
+		names = append(names, m.EventName)
+	}
+	suite.ElementsMatch([]string{"OrderCreated", "OrderPaid", "OrderDeleted"}, names)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenPendingMessages_WhenMarked_ThenShouldUpdateStats() {
+	suite.saveOrderWithMessage("a")
+	suite.saveOrderWithMessage("b")
+	suite.saveOrderWithMessage("c")
+	repo := NewOutboxRepository(suite.Db, suite.Database.Dialect)
+	now := time.Now().UTC()
+
+	suite.NoError(repo.MarkPublished(context.Background(), "msg-a", now))
+	suite.NoError(repo.MarkFailed(context.Background(), "msg-b", "broker down", now.Add(time.Hour)))
+
+	pending, err := repo.FetchPending(context.Background(), now, 10)
+	suite.NoError(err)
+	suite.Len(pending, 1)
+	suite.Equal("msg-c", pending[0].ID)
+
+	pending, err = repo.FetchPending(context.Background(), now.Add(2*time.Hour), 10)
+	suite.NoError(err)
+	suite.Len(pending, 2)
+	suite.Equal("msg-b", pending[0].ID)
+	suite.Equal(1, pending[0].Attempts)
+	suite.Equal("broker down", pending[0].LastError)
+
+	stats, err := repo.Stats(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(2), stats.Pending)
+	suite.Equal(int64(1), stats.Failing)
+	suite.Equal(int64(1), stats.Published)
+	suite.NotNil(stats.OldestPendingAt)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenADeadMessage_WhenFetchPending_ThenShouldSkipItAndCountItAsDead() {
+	suite.saveOrderWithMessage("a")
+	suite.saveOrderWithMessage("b")
+	repo := NewOutboxRepository(suite.Db, suite.Database.Dialect)
+	now := time.Now().UTC()
+
+	suite.NoError(repo.MarkDead(context.Background(), "msg-a", "broker down", now))
+
+	pending, err := repo.FetchPending(context.Background(), now.Add(time.Hour), 10)
+	suite.NoError(err)
+	suite.Require().Len(pending, 1)
+	suite.Equal("msg-b", pending[0].ID)
+
+	stats, err := repo.Stats(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(1), stats.Pending)
+	suite.Equal(int64(0), stats.Failing)
+	suite.Equal(int64(1), stats.Dead)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenAnEmptyOutbox_WhenStats_ThenShouldReturnZeros() {
+	stats, err := NewOutboxRepository(suite.Db, suite.Database.Dialect).Stats(context.Background())
+	suite.NoError(err)
+	suite.Equal(entity.OutboxStats{}, stats)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenACreatedOrder_WhenRelayRuns_ThenShouldPublishOrderCreated() {
+	createOrder := usecase.NewCreateOrderUseCase(NewOrderRepository(suite.Db, suite.Database.Dialect), NewIdempotencyRepository(suite.Db, suite.Database.Dialect), event.NewOrderCreatedFactory(), events.NewEventDispatcher())
+	_, err := createOrder.Execute(context.Background(), usecase.OrderInputDTO{
+		ID:       "a",
+		Currency: "BRL",
+		Items:    []usecase.OrderItemInputDTO{{SKU: "SKU-1", Quantity: 2, UnitPrice: 1000, TaxRateBps: 1000}},
+	})
+	suite.NoError(err)
+
+	publisher := events.NewInMemoryPublisher()
+	relay := outbox.NewRelay(NewOutboxRepository(suite.Db, suite.Database.Dialect), publisher)
+	_, err = relay.RelayBatch(context.Background())
+	suite.NoError(err)
+
+	messages := publisher.Messages()
+	suite.Len(messages, 1)
+	suite.Equal("OrderCreated", messages[0].Name)
+	suite.Equal("a", messages[0].Key)
+	suite.Equal(events.CloudEventsContentType, messages[0].ContentType)
+	ce, err := events.ParseCloudEvent(messages[0].Payload)
+	suite.NoError(err)
+	suite.Equal(messages[0].ID, ce.ID)
+	suite.Equal("OrderCreated", ce.Type)
+	suite.Equal("a", ce.Subject)
+	var payload usecase.OrderOutputDTO
+	suite.NoError(json.Unmarshal(ce.Data, &payload))
+	suite.Equal(int64(2200), payload.FinalPrice.Amount)
+
+	stats, err := NewOutboxRepository(suite.Db, suite.Database.Dialect).Stats(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(0), stats.Pending)
+	suite.Equal(int64(1), stats.Published)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenAnExpiredTimeout_WhenCreateOrder_ThenShouldNotSaveOrder() {
+	createOrder := usecase.NewCreateOrderUseCase(NewOrderRepository(suite.Db, suite.Database.Dialect), NewIdempotencyRepository(suite.Db, suite.Database.Dialect), event.NewOrderCreatedFactory(), events.NewEventDispatcher())
+	createOrder.Timeout = time.Nanosecond
+	time.Sleep(time.Millisecond)
+
+	_, err := createOrder.Execute(context.Background(), usecase.OrderInputDTO{
+		ID:       "a",
+		Currency: "BRL",
+		Items:    []usecase.OrderItemInputDTO{{SKU: "SKU-1", Quantity: 2, UnitPrice: 1000, TaxRateBps: 1000}},
+	})
+	suite.ErrorIs(err, context.DeadlineExceeded)
+
+	stats, err := NewOutboxRepository(suite.Db, suite.Database.Dialect).Stats(context.Background())
+	suite.NoError(err)
+	suite.Equal(entity.OutboxStats{}, stats)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGivenAnInvalidOrder_WhenCreateOrder_ThenShouldReturnFieldErrorsAndSaveNothing() {
+	createOrder := usecase.NewCreateOrderUseCase(NewOrderRepository(suite.Db, suite.Database.Dialect), NewIdempotencyRepository(suite.Db, suite.Database.Dialect), event.NewOrderCreatedFactory(), events.NewEventDispatcher())
+
+	_, err := createOrder.Execute(context.Background(), usecase.OrderInputDTO{
+		ID:       "a",
+		Currency: "BRL",
+		Items:    []usecase.OrderItemInputDTO{{Quantity: 1, UnitPrice: 0}},
+	})
+	var validation *entity.ValidationError
+	suite.Require().ErrorAs(err, &validation)
+	suite.Equal([]entity.FieldError{
+		{Field: "items[0].sku", Err: entity.ErrRequired},
+		{Field: "items[0].unit_price", Err: entity.ErrNotPositive},
+	}, validation.Fields)
+
+	stats, err := NewOutboxRepository(suite.Db, suite.Database.Dialect).Stats(context.Background())
+	suite.NoError(err)
+	suite.Equal(entity.OutboxStats{}, stats)
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/infra/database/migrations"
+
+	// drivers for every dialect under test
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// testDatabase is a database the repository contract suites run against.
+type testDatabase struct {
+	Dialect dialect.Dialect
+	DSN     string
+}
+
+// testDatabases returns an in-memory SQLite database, plus MySQL and
+// PostgreSQL when TEST_MYSQL_DSN or TEST_POSTGRES_DSN point at a scratch
+// database. The suites empty its tables before every test.
+func testDatabases() []testDatabase {
+	databases := []testDatabase{{Dialect: dialect.SQLite, DSN: dialect.SQLite.DSN(dialect.DSNConfig{})}}
+	if dsn := os.Getenv("TEST_MYSQL_DSN"); dsn != "" {
+		databases = append(databases, testDatabase{Dialect: dialect.MySQL, DSN: dsn})
+	}
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		databases = append(databases, testDatabase{Dialect: dialect.Postgres, DSN: dsn})
+	}
+	return databases
+}
+
+// forEachTestDatabase runs test once per database in testDatabases.
+func forEachTestDatabase(t *testing.T, test func(t *testing.T, database testDatabase)) {
+	for _, database := range testDatabases() {
+		database := database
+		t.Run(string(database.Dialect), func(t *testing.T) {
+			test(t, database)
+		})
+	}
+}
+
+// openTestDB returns the database migrated to the latest schema and with
+// empty tables.
+func openTestDB(database testDatabase) (*sql.DB, error) {
+	db, err := sql.Open(database.Dialect.DriverName(), database.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if database.Dialect == dialect.SQLite {
+		// every connection to :memory: is a separate database
+		db.SetMaxOpenConns(1)
+	}
+	migrator, err := migrations.NewMigrator(db, database.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := migrator.Up(); err != nil {
+		return nil, err
+	}
+	for _, table := range []string{"order_items", "orders", "outbox", "processed_events", "order_summaries", "idempotency_keys"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"context"
+
+	"CleanArch/internal/infra/auth"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// NewDirectiveRoot implements the schema directives. @hasPermission checks
+// the principal stored by the HTTP authentication middleware; with enforce
+// false, as when no verification key is configured, every request passes.
+func NewDirectiveRoot(enforce bool) DirectiveRoot {
+	return DirectiveRoot{
+		HasPermission: func(ctx context.Context, obj interface{}, next graphql.Resolver, permission string) (interface{}, error) {
+			if enforce {
+				if err := auth.Authorize(ctx, permission); err != nil {
+					return nil, toGraphQLError(ctx, err)
+				}
+			}
+			return next(ctx)
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"context"
+	"log"
+
+	"CleanArch/internal/infra/apierror"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// toGraphQLError tags errors with an extensions.code so clients can tell them
+// apart without parsing messages; validation errors also list the invalid
+// fields under extensions.fields.
+func toGraphQLError(ctx context.Context, err error) error {
+	problem := apierror.From(err)
+	if problem.Kind == apierror.KindInternal {
+		log.Printf("graphql: %v", err)
+	}
+	extensions := map[string]interface{}{"code": problem.GraphQLCode()}
+	if len(problem.Fields) > 0 {
+		extensions["fields"] = problem.Fields
+	}
+	return &gqlerror.Error{
+		Err:        err,
+		Message:    problem.Detail,
+		Path:       graphql.GetPath(ctx),
+		Extensions: extensions,
+	}
+}
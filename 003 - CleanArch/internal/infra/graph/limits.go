@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"CleanArch/internal/infra/graph/model"
+	"CleanArch/internal/usecase"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// DepthLimit is a gqlgen extension that rejects operations nesting fields
+// deeper than Max before any resolver runs. Introspection fields are not
+// counted, so tools can still load the schema.
+type DepthLimit struct {
+	Max int
+}
+
+var (
+	_ graphql.HandlerExtension        = DepthLimit{}
+	_ graphql.OperationContextMutator = DepthLimit{}
+)
+
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (DepthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(_ context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	if oc.Operation == nil {
+		return nil
+	}
+	if depth := selectionDepth(oc.Operation.SelectionSet); depth > d.Max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Max)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth follows fragments, which validation guarantees are acyclic.
+func selectionDepth(set ast.SelectionSet) int {
+	deepest := 0
+	for _, selection := range set {
+		var depth int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		deepest = max(deepest, depth)
+	}
+	return deepest
+}
+
+// newComplexityRoot weighs a page of orders by the page size it asks for,
+// so first: 100 costs a hundred times one order.
+func newComplexityRoot() ComplexityRoot {
+	var c ComplexityRoot
+	c.Query.Orders = func(childComplexity int, first *int, _ *string, _ *model.OrderFilter, _ *model.OrderSort) int {
+		pageSize := usecase.DefaultOrdersPageSize
+		if first != nil && *first > 0 {
+			pageSize = min(*first, usecase.MaxOrdersPageSize)
+		}
+		return 1 + pageSize*childComplexity
+	}
+	return c
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/usecase"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRepository records each FindByIDs call; other methods are unused.
+type batchRepository struct {
+	entity.OrderRepositoryInterface
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (r *batchRepository) FindByIDs(_ context.Context, ids []string) ([]entity.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, ids)
+	var orders []entity.Order
+	for _, id := range ids {
+		if id != "missing" {
+			orders = append(orders, entity.Order{ID: id, Status: entity.OrderStatusPending})
+		}
+	}
+	return orders, nil
+}
+
+func TestGivenSeveralOrderFields_WhenQueried_ThenShouldLoadThemInOneBatch(t *testing.T) {
+	repository := &batchRepository{}
+	resolver := &Resolver{GetOrderUseCase: *usecase.NewGetOrderUseCase(repository)}
+	c := client.New(NewServer(resolver, ServerConfig{}))
+
+	var response struct {
+		A, B *struct{ ID string }
+		C    *struct{ ID string }
+	}
+	err := c.Post(`{ a: order(id: "1") { id } b: order(id: "2") { id } c: order(id: "missing") { id } }`, &response)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "order not found")
+	assert.Equal(t, "1", response.A.ID)
+	assert.Equal(t, "2", response.B.ID)
+	assert.Nil(t, response.C)
+	require.Len(t, repository.batches, 1)
+	assert.ElementsMatch(t, []string{"1", "2", "missing"}, repository.batches[0])
+}
+
+func TestGivenADeepQuery_WhenOverTheDepthLimit_ThenShouldRejectIt(t *testing.T) {
+	c := client.New(NewServer(&Resolver{}, ServerConfig{DepthLimit: 3}))
+
+	var response map[string]any
+	err := c.Post(`query { ...page } fragment page on Query { orders { edges { node { items { sku } } } } }`, &response)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "operation has depth 5, which exceeds the limit of 3")
+}
+
+func TestGivenALargePage_WhenOverTheComplexityLimit_ThenShouldRejectIt(t *testing.T) {
+	c := client.New(NewServer(&Resolver{}, ServerConfig{ComplexityLimit: 300}))
+
+	var response map[string]any
+	err := c.Post(`{ orders(first: 100) { edges { node { id status } } } }`, &response)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "operation has complexity 401, which exceeds the limit of 300")
+}
+
+func TestGivenIntrospectionOff_WhenTheSchemaIsQueried_ThenShouldRejectIt(t *testing.T) {
+	query := `{ __schema { queryType { name } } }`
+
+	var response map[string]any
+	err := client.New(NewServer(&Resolver{}, ServerConfig{})).Post(query, &response)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "introspection disabled")
+
+	err = client.New(NewServer(&Resolver{}, ServerConfig{Introspection: true})).Post(query, &response)
+	require.NoError(t, err)
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/dataloader"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type loadersKey struct{}
+
+// loaders batch the lookups made by the resolvers of one operation.
+type loaders struct {
+	orders *dataloader.Loader[string, usecase.OrderOutputDTO]
+}
+
+// withLoaders gives each operation fresh loaders, so cached orders never
+// outlive the request that loaded them.
+func (r *Resolver) withLoaders(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	l := &loaders{
+		orders: dataloader.New(func(ctx context.Context, ids []string) (map[string]usecase.OrderOutputDTO, error) {
+			return r.GetOrderUseCase.ExecuteMany(ctx, usecase.GetOrdersInputDTO{IDs: ids})
+		}),
+	}
+	return next(context.WithValue(ctx, loadersKey{}, l))
+}
+
+// loadOrder fetches an order through the operation's loader, so sibling
+// order(id:) fields share one query.
+func (r *Resolver) loadOrder(ctx context.Context, id string) (usecase.OrderOutputDTO, error) {
+	l, ok := ctx.Value(loadersKey{}).(*loaders)
+	if !ok {
+		return r.GetOrderUseCase.Execute(ctx, usecase.GetOrderInputDTO{ID: id})
+	}
+	order, err := l.orders.Load(ctx, id)
+	if errors.Is(err, dataloader.ErrNotFound) {
+		return order, entity.ErrOrderNotFound
+	}
+	return order, err
+}
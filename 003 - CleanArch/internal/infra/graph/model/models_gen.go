@@ -2,21 +2,248 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Amount in the currency's minor units (cents).
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
 type Mutation struct {
 }
 
 type Order struct {
-	ID         string  `json:"id"`
-	Price      float64 `json:"Price"`
-	Tax        float64 `json:"Tax"`
-	FinalPrice float64 `json:"FinalPrice"`
+	ID          string       `json:"id"`
+	Currency    string       `json:"currency"`
+	Items       []*OrderItem `json:"items"`
+	Price       *Money       `json:"price"`
+	Tax         *Money       `json:"tax"`
+	FinalPrice  *Money       `json:"finalPrice"`
+	Status      OrderStatus  `json:"status"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	PaidAt      *time.Time   `json:"paidAt,omitempty"`
+	ShippedAt   *time.Time   `json:"shippedAt,omitempty"`
+	DeliveredAt *time.Time   `json:"deliveredAt,omitempty"`
+	CancelledAt *time.Time   `json:"cancelledAt,omitempty"`
+	RefundedAt  *time.Time   `json:"refundedAt,omitempty"`
+	// Subject of the token that created the order, when authentication is on.
+	CreatedBy *string `json:"createdBy,omitempty"`
+	Version   int64   `json:"version"`
+}
+
+type OrderConnection struct {
+	Edges    []*OrderEdge `json:"edges"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+}
+
+// Omitted fields match every new order; prices are final prices in minor units.
+type OrderCreatedFilter struct {
+	Currency *string `json:"currency,omitempty"`
+	MinPrice *int64  `json:"minPrice,omitempty"`
+	MaxPrice *int64  `json:"maxPrice,omitempty"`
+}
+
+type OrderEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Order `json:"node"`
+}
+
+// Prices are final prices in minor units.
+type OrderFilter struct {
+	Status      *OrderStatus `json:"status,omitempty"`
+	MinPrice    *int64       `json:"minPrice,omitempty"`
+	MaxPrice    *int64       `json:"maxPrice,omitempty"`
+	CreatedFrom *time.Time   `json:"createdFrom,omitempty"`
+	CreatedTo   *time.Time   `json:"createdTo,omitempty"`
 }
 
 type OrderInput struct {
-	ID    string  `json:"id"`
-	Price float64 `json:"Price"`
-	Tax   float64 `json:"Tax"`
+	// Omit to let the server generate a ULID.
+	ID       *string           `json:"id,omitempty"`
+	Currency string            `json:"currency"`
+	Items    []*OrderItemInput `json:"items"`
+}
+
+type OrderItem struct {
+	Sku        string `json:"sku"`
+	Quantity   int64  `json:"quantity"`
+	UnitPrice  *Money `json:"unitPrice"`
+	TaxRateBps int64  `json:"taxRateBps"`
+	Subtotal   *Money `json:"subtotal"`
+	Tax        *Money `json:"tax"`
+}
+
+type OrderItemInput struct {
+	Sku        string `json:"sku"`
+	Quantity   int64  `json:"quantity"`
+	UnitPrice  int64  `json:"unitPrice"`
+	TaxRateBps int64  `json:"taxRateBps"`
+}
+
+type OrderSort struct {
+	Field     OrderSortField `json:"field"`
+	Direction SortDirection  `json:"direction"`
+}
+
+// Omitted fields match every change; statuses are the new statuses to watch.
+type OrderStatusChangedFilter struct {
+	Ids      []string      `json:"ids,omitempty"`
+	Statuses []OrderStatus `json:"statuses,omitempty"`
+}
+
+// Omitted fields keep their current value.
+type OrderUpdateInput struct {
+	Currency *string           `json:"currency,omitempty"`
+	Items    []*OrderItemInput `json:"items,omitempty"`
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
 }
 
 type Query struct {
 }
+
+// Subscriptions run over WebSocket on /query. A client that falls too far
+// behind has its subscription completed and should refetch before subscribing
+// again.
+type Subscription struct {
+}
+
+type OrderSortField string
+
+const (
+	OrderSortFieldCreatedAt  OrderSortField = "CREATED_AT"
+	OrderSortFieldFinalPrice OrderSortField = "FINAL_PRICE"
+)
+
+var AllOrderSortField = []OrderSortField{
+	OrderSortFieldCreatedAt,
+	OrderSortFieldFinalPrice,
+}
+
+func (e OrderSortField) IsValid() bool {
+	switch e {
+	case OrderSortFieldCreatedAt, OrderSortFieldFinalPrice:
+		return true
+	}
+	return false
+}
+
+func (e OrderSortField) String() string {
+	return string(e)
+}
+
+func (e *OrderSortField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderSortField", str)
+	}
+	return nil
+}
+
+func (e OrderSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "PENDING"
+	OrderStatusPaid      OrderStatus = "PAID"
+	OrderStatusShipped   OrderStatus = "SHIPPED"
+	OrderStatusDelivered OrderStatus = "DELIVERED"
+	OrderStatusCancelled OrderStatus = "CANCELLED"
+	OrderStatusRefunded  OrderStatus = "REFUNDED"
+)
+
+var AllOrderStatus = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusPaid,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCancelled,
+	OrderStatusRefunded,
+}
+
+func (e OrderStatus) IsValid() bool {
+	switch e {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled, OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (e OrderStatus) String() string {
+	return string(e)
+}
+
+func (e *OrderStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderStatus", str)
+	}
+	return nil
+}
+
+func (e OrderStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
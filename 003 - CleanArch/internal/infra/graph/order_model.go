@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"strings"
+	"time"
+
+	"CleanArch/internal/infra/graph/model"
+	"CleanArch/internal/usecase"
+)
+
+func toOrderModel(o usecase.OrderOutputDTO) *model.Order {
+	items := make([]*model.OrderItem, 0, len(o.Items))
+	for _, item := range o.Items {
+		items = append(items, &model.OrderItem{
+			Sku:        item.SKU,
+			Quantity:   item.Quantity,
+			UnitPrice:  toMoneyModel(item.UnitPrice),
+			TaxRateBps: item.TaxRateBps,
+			Subtotal:   toMoneyModel(item.Subtotal),
+			Tax:        toMoneyModel(item.Tax),
+		})
+	}
+	return &model.Order{
+		ID:          o.ID,
+		Currency:    o.Currency,
+		Items:       items,
+		Price:       toMoneyModel(o.Price),
+		Tax:         toMoneyModel(o.Tax),
+		FinalPrice:  toMoneyModel(o.FinalPrice),
+		Status:      model.OrderStatus(strings.ToUpper(o.Status)),
+		CreatedAt:   o.CreatedAt,
+		PaidAt:      o.PaidAt,
+		ShippedAt:   o.ShippedAt,
+		DeliveredAt: o.DeliveredAt,
+		CancelledAt: o.CancelledAt,
+		RefundedAt:  o.RefundedAt,
+		Version:     o.Version,
+		CreatedBy:   optionalString(o.CreatedBy),
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// utcTime converts a filter time to UTC, the zone created_at is stored in.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func toItemInputs(items []*model.OrderItemInput) []usecase.OrderItemInputDTO {
+	var result []usecase.OrderItemInputDTO
+	for _, item := range items {
+		result = append(result, usecase.OrderItemInputDTO{
+			SKU:        item.Sku,
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+			TaxRateBps: item.TaxRateBps,
+		})
+	}
+	return result
+}
+
+func toMoneyModel(m usecase.MoneyDTO) *model.Money {
+	return &model.Money{Amount: m.Amount, Currency: m.Currency}
+}
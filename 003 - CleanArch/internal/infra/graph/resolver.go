@@ -1,12 +1,25 @@
 package graph
 
-import "CleanArch/internal/usecase"
+import (
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+)
 
 // This file will not be regenerated automatically.
 //
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	CreateOrderUseCase usecase.CreateOrderUseCase
-	ListOrdersUseCase  usecase.ListOrdersUseCase
+	CreateOrderUseCase  usecase.CreateOrderUseCase
+	ListOrdersUseCase   usecase.ListOrdersUseCase
+	GetOrderUseCase     usecase.GetOrderUseCase
+	PayOrderUseCase     usecase.PayOrderUseCase
+	ShipOrderUseCase    usecase.ShipOrderUseCase
+	DeliverOrderUseCase usecase.DeliverOrderUseCase
+	CancelOrderUseCase  usecase.CancelOrderUseCase
+	RefundOrderUseCase  usecase.RefundOrderUseCase
+	UpdateOrderUseCase  usecase.UpdateOrderUseCase
+	DeleteOrderUseCase  usecase.DeleteOrderUseCase
+	// OrderEvents feeds the subscriptions.
+	OrderEvents *events.Broadcaster
 }
@@ -5,43 +5,159 @@ package graph
 // Code generated by github.com/99designs/gqlgen version v0.17.60
 
 import (
+	"CleanArch/internal/infra/auth"
 	"CleanArch/internal/infra/graph/model"
 	"CleanArch/internal/usecase"
 	"context"
+	"strings"
 )
 
 // CreateOrder is the resolver for the createOrder field.
 func (r *mutationResolver) CreateOrder(ctx context.Context, input *model.OrderInput) (*model.Order, error) {
 	dto := usecase.OrderInputDTO{
-		ID:    input.ID,
-		Price: float64(input.Price),
-		Tax:   float64(input.Tax),
+		Currency:  input.Currency,
+		Items:     toItemInputs(input.Items),
+		CreatedBy: auth.Subject(ctx),
 	}
-	output, err := r.CreateOrderUseCase.Execute(dto)
+	if input.ID != nil {
+		dto.ID = *input.ID
+	}
+	output, err := r.CreateOrderUseCase.Execute(ctx, dto)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// PayOrder is the resolver for the payOrder field.
+func (r *mutationResolver) PayOrder(ctx context.Context, id string) (*model.Order, error) {
+	output, err := r.PayOrderUseCase.Execute(ctx, usecase.OrderStatusInputDTO{ID: id})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// ShipOrder is the resolver for the shipOrder field.
+func (r *mutationResolver) ShipOrder(ctx context.Context, id string) (*model.Order, error) {
+	output, err := r.ShipOrderUseCase.Execute(ctx, usecase.OrderStatusInputDTO{ID: id})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// DeliverOrder is the resolver for the deliverOrder field.
+func (r *mutationResolver) DeliverOrder(ctx context.Context, id string) (*model.Order, error) {
+	output, err := r.DeliverOrderUseCase.Execute(ctx, usecase.OrderStatusInputDTO{ID: id})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// CancelOrder is the resolver for the cancelOrder field.
+func (r *mutationResolver) CancelOrder(ctx context.Context, id string) (*model.Order, error) {
+	output, err := r.CancelOrderUseCase.Execute(ctx, usecase.OrderStatusInputDTO{ID: id})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// RefundOrder is the resolver for the refundOrder field.
+func (r *mutationResolver) RefundOrder(ctx context.Context, id string) (*model.Order, error) {
+	output, err := r.RefundOrderUseCase.Execute(ctx, usecase.OrderStatusInputDTO{ID: id})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// UpdateOrder is the resolver for the updateOrder field.
+func (r *mutationResolver) UpdateOrder(ctx context.Context, id string, version int64, input model.OrderUpdateInput) (*model.Order, error) {
+	dto := usecase.UpdateOrderInputDTO{
+		ID:      id,
+		Version: version,
+		Items:   toItemInputs(input.Items),
+	}
+	if input.Currency != nil {
+		dto.Currency = *input.Currency
+	}
+	output, err := r.UpdateOrderUseCase.Execute(ctx, dto)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// DeleteOrder is the resolver for the deleteOrder field.
+func (r *mutationResolver) DeleteOrder(ctx context.Context, id string, version int64) (bool, error) {
+	_, err := r.DeleteOrderUseCase.Execute(ctx, usecase.DeleteOrderInputDTO{ID: id, Version: version})
 	if err != nil {
-		return nil, err
+		return false, toGraphQLError(ctx, err)
 	}
-	return &model.Order{
-		ID:         output.ID,
-		Price:      float64(output.Price),
-		Tax:        float64(output.Tax),
-		FinalPrice: float64(output.FinalPrice),
-	}, nil
+	return true, nil
 }
 
 // Orders is the resolver for the orders field.
-func (r *queryResolver) Orders(ctx context.Context) ([]*model.Order, error) {
-		orders, err := r.ListOrdersUseCase.ListOrders()
+func (r *queryResolver) Orders(ctx context.Context, first *int, after *string, filter *model.OrderFilter, sort *model.OrderSort) (*model.OrderConnection, error) {
+	input := usecase.ListOrdersInputDTO{}
+	if first != nil {
+		input.PageSize = *first
+	}
+	if after != nil {
+		input.After = *after
+	}
+	if filter != nil {
+		if filter.Status != nil {
+			input.Status = strings.ToLower(filter.Status.String())
+		}
+		input.MinPrice = filter.MinPrice
+		input.MaxPrice = filter.MaxPrice
+		input.CreatedFrom = utcTime(filter.CreatedFrom)
+		input.CreatedTo = utcTime(filter.CreatedTo)
+	}
+	if sort != nil {
+		input.SortBy = strings.ToLower(sort.Field.String())
+		input.Descending = sort.Direction == model.SortDirectionDesc
+	}
+
+	output, err := r.ListOrdersUseCase.ListOrders(ctx, input)
 	if err != nil {
-		return nil, err
+		return nil, toGraphQLError(ctx, err)
 	}
 
-	var ordersModel []*model.Order
-	for _, o := range orders.Orders {
-		ordersModel = append(ordersModel, &model.Order{ID: o.ID, Price: o.Price, Tax: o.Tax, FinalPrice: o.FinalPrice})
+	connection := &model.OrderConnection{
+		Edges:    make([]*model.OrderEdge, 0, len(output.Edges)),
+		PageInfo: &model.PageInfo{HasNextPage: output.PageInfo.HasNextPage},
+	}
+	for _, edge := range output.Edges {
+		connection.Edges = append(connection.Edges, &model.OrderEdge{Cursor: edge.Cursor, Node: toOrderModel(edge.Order)})
+	}
+	if output.PageInfo.EndCursor != "" {
+		connection.PageInfo.EndCursor = &output.PageInfo.EndCursor
 	}
+	return connection, nil
+}
 
-	return ordersModel, nil
+// Order is the resolver for the order field.
+func (r *queryResolver) Order(ctx context.Context, id string) (*model.Order, error) {
+	output, err := r.loadOrder(ctx, id)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return toOrderModel(output), nil
+}
+
+// OrderCreated is the resolver for the orderCreated field.
+func (r *subscriptionResolver) OrderCreated(ctx context.Context, filter *model.OrderCreatedFilter) (<-chan *model.Order, error) {
+	return r.watchOrders(ctx, matchOrderCreated(filter))
+}
+
+// OrderStatusChanged is the resolver for the orderStatusChanged field.
+func (r *subscriptionResolver) OrderStatusChanged(ctx context.Context, filter *model.OrderStatusChangedFilter) (<-chan *model.Order, error) {
+	return r.watchOrders(ctx, matchOrderStatusChanged(filter))
 }
 
 // Mutation returns MutationResolver implementation.
@@ -50,5 +166,9 @@ func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
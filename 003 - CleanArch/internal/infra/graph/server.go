@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/infra/auth"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ServerConfig sets the limits applied to every operation. A zero
+// ComplexityLimit or DepthLimit turns that limit off.
+type ServerConfig struct {
+	// Verifier, when set, enforces @hasPermission.
+	Verifier            *auth.Verifier
+	ComplexityLimit     int
+	DepthLimit          int
+	PersistedQueryCache int
+	// Introspection lets clients query the schema, as the playground does.
+	// It is meant for development only.
+	Introspection bool
+}
+
+// NewServer serves resolver over HTTP and, for subscriptions, WebSocket.
+// Browsers cannot set headers on a WebSocket, so with a verifier the bearer
+// token may also come in the "Authorization" field of the connection_init
+// payload. Clients may send a query's SHA-256 hash instead of its text once
+// the server has seen it (automatic persisted queries).
+func NewServer(resolver *Resolver, config ServerConfig) *handler.Server {
+	srv := handler.New(NewExecutableSchema(Config{
+		Resolvers:  resolver,
+		Directives: NewDirectiveRoot(config.Verifier != nil),
+		Complexity: newComplexityRoot(),
+	}))
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              websocketInit(config.Verifier),
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	if config.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	if config.PersistedQueryCache > 0 {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](config.PersistedQueryCache),
+		})
+	}
+	if config.ComplexityLimit > 0 {
+		srv.Use(extension.FixedComplexityLimit(config.ComplexityLimit))
+	}
+	if config.DepthLimit > 0 {
+		srv.Use(DepthLimit{Max: config.DepthLimit})
+	}
+	srv.AroundOperations(resolver.withLoaders)
+	return srv
+}
+
+func websocketInit(verifier *auth.Verifier) transport.WebsocketInitFunc {
+	if verifier == nil {
+		return nil
+	}
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		header := payload.Authorization()
+		if header == "" {
+			return ctx, nil, nil
+		}
+		principal, err := verifier.VerifyAuthorization(header)
+		if err != nil {
+			return ctx, nil, err
+		}
+		return auth.WithPrincipal(ctx, principal), nil, nil
+	}
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"CleanArch/internal/infra/graph/model"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// statusEvents are the events that move an order to a new status.
+var statusEvents = []string{"OrderPaid", "OrderShipped", "OrderDelivered", "OrderCancelled", "OrderRefunded"}
+
+// watchOrders streams the order carried by each event that match accepts,
+// until the client unsubscribes. The channel is closed early, completing the
+// subscription, if the client falls too far behind.
+func (r *Resolver) watchOrders(ctx context.Context, match func(name string, order usecase.OrderOutputDTO) bool) (<-chan *model.Order, error) {
+	if r.OrderEvents == nil {
+		return nil, gqlerror.Errorf("subscriptions are not available")
+	}
+	subscription := r.OrderEvents.Subscribe(func(event events.EventInterface) bool {
+		order, ok := event.GetPayload().(usecase.OrderOutputDTO)
+		return ok && match(event.GetName(), order)
+	})
+
+	orders := make(chan *model.Order)
+	go func() {
+		defer close(orders)
+		defer subscription.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-subscription.C:
+				if !ok {
+					return
+				}
+				select {
+				case orders <- toOrderModel(event.GetPayload().(usecase.OrderOutputDTO)):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return orders, nil
+}
+
+func matchOrderCreated(filter *model.OrderCreatedFilter) func(string, usecase.OrderOutputDTO) bool {
+	return func(name string, order usecase.OrderOutputDTO) bool {
+		if name != "OrderCreated" {
+			return false
+		}
+		if filter == nil {
+			return true
+		}
+		return (filter.Currency == nil || strings.EqualFold(*filter.Currency, order.Currency)) &&
+			(filter.MinPrice == nil || order.FinalPrice.Amount >= *filter.MinPrice) &&
+			(filter.MaxPrice == nil || order.FinalPrice.Amount <= *filter.MaxPrice)
+	}
+}
+
+func matchOrderStatusChanged(filter *model.OrderStatusChangedFilter) func(string, usecase.OrderOutputDTO) bool {
+	return func(name string, order usecase.OrderOutputDTO) bool {
+		if !slices.Contains(statusEvents, name) {
+			return false
+		}
+		if filter == nil {
+			return true
+		}
+		return (len(filter.Ids) == 0 || slices.Contains(filter.Ids, order.ID)) &&
+			(len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, model.OrderStatus(strings.ToUpper(order.Status))))
+	}
+}
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CleanArch/internal/event"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderResponse struct {
+	ID         string
+	Status     string
+	FinalPrice struct{ Amount int64 }
+}
+
+func publishOrder(t *testing.T, broadcaster *events.Broadcaster, e events.EventInterface, order usecase.OrderOutputDTO) {
+	e.SetPayload(order)
+	require.NoError(t, broadcaster.Handle(context.Background(), e))
+}
+
+// subscribe starts query over WebSocket and waits until it is listening.
+func subscribe(t *testing.T, broadcaster *events.Broadcaster, query string) *client.Subscription {
+	srv := NewServer(&Resolver{OrderEvents: broadcaster}, ServerConfig{})
+	subscription := client.New(srv).Websocket(query)
+	require.Eventually(t, func() bool { return broadcaster.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+	return subscription
+}
+
+func TestGivenAnOrderCreatedSubscription_WhenOrdersAreCreated_ThenShouldReceiveTheMatchingOnes(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	subscription := subscribe(t, broadcaster, `subscription { orderCreated(filter: {minPrice: 1000}) { id status finalPrice { amount } } }`)
+	defer subscription.Close()
+
+	publishOrder(t, broadcaster, event.NewOrderCreated(), usecase.OrderOutputDTO{ID: "cheap", Status: "pending", FinalPrice: usecase.MoneyDTO{Amount: 500}})
+	publishOrder(t, broadcaster, event.NewOrderPaid(), usecase.OrderOutputDTO{ID: "paid", Status: "paid", FinalPrice: usecase.MoneyDTO{Amount: 5000}})
+	publishOrder(t, broadcaster, event.NewOrderCreated(), usecase.OrderOutputDTO{ID: "big", Status: "pending", FinalPrice: usecase.MoneyDTO{Amount: 5000}})
+
+	var response struct{ OrderCreated orderResponse }
+	require.NoError(t, subscription.Next(&response))
+	assert.Equal(t, "big", response.OrderCreated.ID)
+	assert.Equal(t, "PENDING", response.OrderCreated.Status)
+	assert.Equal(t, int64(5000), response.OrderCreated.FinalPrice.Amount)
+}
+
+func TestGivenAnOrderStatusChangedSubscription_WhenOrdersChange_ThenShouldReceiveTheWatchedOrders(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	subscription := subscribe(t, broadcaster, `subscription { orderStatusChanged(filter: {ids: ["a"], statuses: [SHIPPED]}) { id status } }`)
+	defer subscription.Close()
+
+	publishOrder(t, broadcaster, event.NewOrderPaid(), usecase.OrderOutputDTO{ID: "a", Status: "paid"})
+	publishOrder(t, broadcaster, event.NewOrderShipped(), usecase.OrderOutputDTO{ID: "b", Status: "shipped"})
+	publishOrder(t, broadcaster, event.NewOrderUpdated(), usecase.OrderOutputDTO{ID: "a", Status: "shipped"})
+	publishOrder(t, broadcaster, event.NewOrderShipped(), usecase.OrderOutputDTO{ID: "a", Status: "shipped"})
+
+	var response struct{ OrderStatusChanged orderResponse }
+	require.NoError(t, subscription.Next(&response))
+	assert.Equal(t, "a", response.OrderStatusChanged.ID)
+	assert.Equal(t, "SHIPPED", response.OrderStatusChanged.Status)
+}
@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer is a gqlgen extension that adds a span per operation, under the
+// HTTP request span, and one per resolver call. Fields read straight from a
+// struct are left out to keep traces small.
+type Tracer struct{}
+
+var (
+	_ graphql.HandlerExtension    = Tracer{}
+	_ graphql.ResponseInterceptor = Tracer{}
+	_ graphql.FieldInterceptor    = Tracer{}
+)
+
+var tracer = otel.Tracer("CleanArch/internal/infra/graph")
+
+func (Tracer) ExtensionName() string {
+	return "OpenTelemetry"
+}
+
+func (Tracer) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Tracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	oc := graphql.GetOperationContext(ctx)
+	name := "graphql"
+	var attributes []attribute.KeyValue
+	if oc.Operation != nil {
+		name += " " + string(oc.Operation.Operation)
+		attributes = append(attributes, semconv.GraphqlOperationTypeKey.String(string(oc.Operation.Operation)))
+	}
+	if oc.OperationName != "" {
+		name += " " + oc.OperationName
+		attributes = append(attributes, semconv.GraphqlOperationName(oc.OperationName))
+	}
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+	defer span.End()
+
+	response := next(ctx)
+	if response != nil && len(response.Errors) > 0 {
+		span.SetStatus(codes.Error, response.Errors.Error())
+	}
+	return response
+}
+
+func (Tracer) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	ctx, span := tracer.Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(attribute.String("graphql.field.path", fc.Path().String())))
+	defer span.End()
+
+	result, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/infra/auth"
+	"CleanArch/internal/infra/grpc/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MethodPermissions is the permission each OrderService RPC requires.
+var MethodPermissions = map[string]string{
+	pb.OrderService_CreateOrder_FullMethodName:      auth.PermissionWrite,
+	pb.OrderService_ListOrders_FullMethodName:       auth.PermissionRead,
+	pb.OrderService_GetOrder_FullMethodName:         auth.PermissionRead,
+	pb.OrderService_PayOrder_FullMethodName:         auth.PermissionWrite,
+	pb.OrderService_ShipOrder_FullMethodName:        auth.PermissionWrite,
+	pb.OrderService_DeliverOrder_FullMethodName:     auth.PermissionWrite,
+	pb.OrderService_CancelOrder_FullMethodName:      auth.PermissionWrite,
+	pb.OrderService_RefundOrder_FullMethodName:      auth.PermissionWrite,
+	pb.OrderService_UpdateOrder_FullMethodName:      auth.PermissionWrite,
+	pb.OrderService_DeleteOrder_FullMethodName:      auth.PermissionWrite,
+	pb.OrderService_StreamOrders_FullMethodName:     auth.PermissionRead,
+	pb.OrderService_WatchOrders_FullMethodName:      auth.PermissionRead,
+	pb.OrderService_BulkCreateOrders_FullMethodName: auth.PermissionWrite,
+}
+
+// PublicServices are the services whose methods need no token. Every other
+// method must be listed in the permissions given to the interceptors, so an
+// RPC added without a permission is denied rather than left open.
+var PublicServices = []string{
+	"grpc.health.v1.Health",
+	"grpc.reflection.v1.ServerReflection",
+	"grpc.reflection.v1alpha.ServerReflection",
+}
+
+// AuthUnaryInterceptor verifies the bearer token in the "authorization"
+// metadata of every method listed in permissions. Methods of PublicServices
+// stay open and any other method is denied.
+func AuthUnaryInterceptor(verifier *auth.Verifier, permissions map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		permission, public, err := methodPermission(permissions, info.FullMethod)
+		if err != nil {
+			return nil, apierror.GRPCError(err)
+		}
+		if public {
+			return handler(ctx, req)
+		}
+		ctx, err = authenticate(ctx, verifier, permission)
+		if err != nil {
+			return nil, apierror.GRPCError(err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is AuthUnaryInterceptor for streaming methods; the
+// handler sees the principal through the stream's context.
+func AuthStreamInterceptor(verifier *auth.Verifier, permissions map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		permission, public, err := methodPermission(permissions, info.FullMethod)
+		if err != nil {
+			return apierror.GRPCError(err)
+		}
+		if public {
+			return handler(srv, stream)
+		}
+		ctx, err := authenticate(stream.Context(), verifier, permission)
+		if err != nil {
+			return apierror.GRPCError(err)
+		}
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// methodPermission returns the permission fullMethod requires, or public for
+// the methods of PublicServices.
+func methodPermission(permissions map[string]string, fullMethod string) (permission string, public bool, err error) {
+	if permission, ok := permissions[fullMethod]; ok {
+		return permission, false, nil
+	}
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if slices.Contains(PublicServices, service) {
+		return "", true, nil
+	}
+	return "", false, fmt.Errorf("%w: %s has no permission", auth.ErrPermissionDenied, fullMethod)
+}
+
+// contextStream is a stream whose handler sees ctx instead of the stream's
+// own context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticate(ctx context.Context, verifier *auth.Verifier, permission string) (context.Context, error) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return ctx, auth.ErrUnauthenticated
+	}
+	principal, err := verifier.VerifyAuthorization(values[0])
+	if err != nil {
+		return ctx, err
+	}
+	ctx = auth.WithPrincipal(ctx, principal)
+	return ctx, auth.Authorize(ctx, permission)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CleanArch/internal/infra/auth"
+	"CleanArch/internal/infra/grpc/pb"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// unmappedService stands for an OrderService RPC added without a permission.
+var unmappedService = grpc.ServiceDesc{
+	ServiceName: "pb.OrderService",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Unmapped",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := &emptypb.Empty{}
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req any) (any, error) { return &emptypb.Empty{}, nil }
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{FullMethod: "/pb.OrderService/Unmapped"}, handler)
+		},
+	}},
+}
+
+func authenticatedConn(t *testing.T) *grpc.ClientConn {
+	keys, err := auth.NewStaticKeySet("s3cret")
+	require.NoError(t, err)
+	verifier := auth.NewVerifier(keys, auth.VerifierOptions{})
+	return connect(t, func(server *grpc.Server) {
+		server.RegisterService(&unmappedService, struct{}{})
+		healthpb.RegisterHealthServer(server, grpchealth.NewServer())
+	},
+		grpc.UnaryInterceptor(AuthUnaryInterceptor(verifier, MethodPermissions)),
+		grpc.StreamInterceptor(AuthStreamInterceptor(verifier, MethodPermissions)),
+	)
+}
+
+func writerContext(t *testing.T) context.Context {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "admin", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		Roles:            []string{auth.PermissionWrite},
+	}).SignedString([]byte("s3cret"))
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestGivenAnRPCWithoutAPermission_WhenCalled_ThenShouldBeDeniedEvenWithAValidToken(t *testing.T) {
+	conn := authenticatedConn(t)
+
+	err := conn.Invoke(writerContext(t), "/pb.OrderService/Unmapped", &emptypb.Empty{}, &emptypb.Empty{})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGivenAPublicService_WhenCalledWithoutAToken_ThenShouldPassThrough(t *testing.T) {
+	conn := authenticatedConn(t)
+
+	check, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check.Status)
+}
+
+func TestGivenEveryOrderServiceRPC_WhenListed_ThenShouldHaveAPermission(t *testing.T) {
+	desc := pb.OrderService_ServiceDesc
+	var methods []string
+	for _, m := range desc.Methods {
+		methods = append(methods, "/"+desc.ServiceName+"/"+m.MethodName)
+	}
+	for _, s := range desc.Streams {
+		methods = append(methods, "/"+desc.ServiceName+"/"+s.StreamName)
+	}
+	for _, method := range methods {
+		_, public, err := methodPermission(MethodPermissions, method)
+		assert.NoError(t, err, method)
+		assert.False(t, public, method)
+	}
+}
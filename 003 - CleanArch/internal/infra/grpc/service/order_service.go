@@ -2,60 +2,252 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/infra/auth"
 	"CleanArch/internal/infra/grpc/pb"
 	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type OrderService struct {
 	pb.UnimplementedOrderServiceServer
-	CreateOrderUseCase usecase.CreateOrderUseCase
-	ListOrdersUseCase  usecase.ListOrdersUseCase
+	CreateOrderUseCase  usecase.CreateOrderUseCase
+	ListOrdersUseCase   usecase.ListOrdersUseCase
+	GetOrderUseCase     usecase.GetOrderUseCase
+	PayOrderUseCase     usecase.PayOrderUseCase
+	ShipOrderUseCase    usecase.ShipOrderUseCase
+	DeliverOrderUseCase usecase.DeliverOrderUseCase
+	CancelOrderUseCase  usecase.CancelOrderUseCase
+	RefundOrderUseCase  usecase.RefundOrderUseCase
+	UpdateOrderUseCase  usecase.UpdateOrderUseCase
+	DeleteOrderUseCase  usecase.DeleteOrderUseCase
+	// OrderEvents feeds WatchOrders; without it the RPC is unavailable.
+	OrderEvents *events.Broadcaster
 }
 
-func NewOrderService(createOrderUseCase usecase.CreateOrderUseCase, listOrdersUseCase usecase.ListOrdersUseCase) *OrderService {
+func NewOrderService(
+	createOrderUseCase usecase.CreateOrderUseCase,
+	listOrdersUseCase usecase.ListOrdersUseCase,
+	getOrderUseCase usecase.GetOrderUseCase,
+	payOrderUseCase usecase.PayOrderUseCase,
+	shipOrderUseCase usecase.ShipOrderUseCase,
+	deliverOrderUseCase usecase.DeliverOrderUseCase,
+	cancelOrderUseCase usecase.CancelOrderUseCase,
+	refundOrderUseCase usecase.RefundOrderUseCase,
+	updateOrderUseCase usecase.UpdateOrderUseCase,
+	deleteOrderUseCase usecase.DeleteOrderUseCase,
+) *OrderService {
 	return &OrderService{
-		CreateOrderUseCase: createOrderUseCase,
-		ListOrdersUseCase:  listOrdersUseCase,
+		CreateOrderUseCase:  createOrderUseCase,
+		ListOrdersUseCase:   listOrdersUseCase,
+		GetOrderUseCase:     getOrderUseCase,
+		PayOrderUseCase:     payOrderUseCase,
+		ShipOrderUseCase:    shipOrderUseCase,
+		DeliverOrderUseCase: deliverOrderUseCase,
+		CancelOrderUseCase:  cancelOrderUseCase,
+		RefundOrderUseCase:  refundOrderUseCase,
+		UpdateOrderUseCase:  updateOrderUseCase,
+		DeleteOrderUseCase:  deleteOrderUseCase,
 	}
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, in *pb.CreateOrderRequest) (*pb.CreateOrderResponse, error) {
-	dto := usecase.OrderInputDTO{
-		ID:    in.Id,
-		Price: float64(in.Price),
-		Tax:   float64(in.Tax),
+	dto := toOrderInput(ctx, in)
+	if keys := metadata.ValueFromIncomingContext(ctx, "idempotency-key"); len(keys) > 0 {
+		dto.IdempotencyKey = keys[0]
 	}
-	output, err := s.CreateOrderUseCase.Execute(dto)
+	output, err := s.CreateOrderUseCase.Execute(ctx, dto)
 	if err != nil {
-		return nil, err
+		return nil, apierror.GRPCError(err)
 	}
-	return &pb.CreateOrderResponse{
-		Id:         output.ID,
-		Price:      float32(output.Price),
-		Tax:        float32(output.Tax),
-		FinalPrice: float32(output.FinalPrice),
-	}, nil
+	return toCreateOrderResponse(output), nil
 }
 
-func (s *OrderService) ListOrders(ctx context.Context, in *pb.Blank) (*pb.ListOrdersResponse, error) {
-	output, err := s.ListOrdersUseCase.ListOrders()
+func (s *OrderService) ListOrders(ctx context.Context, in *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	input := toListOrdersInput(in)
+
+	output, err := s.ListOrdersUseCase.ListOrders(ctx, input)
 	if err != nil {
-		return nil, err
+		return nil, apierror.GRPCError(err)
 	}
 
 	var orders []*pb.Order
-	for _, order := range output.Orders {
-		orders = append(orders, &pb.Order{
-			Id:         order.ID,
-			Price:      float32(order.Price),
-			Tax:        float32(order.Tax),
-			FinalPrice: float32(order.FinalPrice),
-		})
+	for _, edge := range output.Edges {
+		orders = append(orders, toProtoOrder(edge.Order))
 	}
 
 	return &pb.ListOrdersResponse{
-		Orders: orders,
-		Total:  output.Total,
+		Orders:      orders,
+		NextCursor:  output.PageInfo.EndCursor,
+		HasNextPage: output.PageInfo.HasNextPage,
 	}, nil
-}
\ No newline at end of file
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, in *pb.GetOrderRequest) (*pb.Order, error) {
+	output, err := s.GetOrderUseCase.Execute(ctx, usecase.GetOrderInputDTO{ID: in.Id})
+	if err != nil {
+		return nil, apierror.GRPCError(err)
+	}
+	return toProtoOrder(output), nil
+}
+
+func (s *OrderService) PayOrder(ctx context.Context, in *pb.OrderIdRequest) (*pb.Order, error) {
+	return changeOrderStatus(ctx, s.PayOrderUseCase.Execute, in)
+}
+
+func (s *OrderService) ShipOrder(ctx context.Context, in *pb.OrderIdRequest) (*pb.Order, error) {
+	return changeOrderStatus(ctx, s.ShipOrderUseCase.Execute, in)
+}
+
+func (s *OrderService) DeliverOrder(ctx context.Context, in *pb.OrderIdRequest) (*pb.Order, error) {
+	return changeOrderStatus(ctx, s.DeliverOrderUseCase.Execute, in)
+}
+
+func (s *OrderService) CancelOrder(ctx context.Context, in *pb.OrderIdRequest) (*pb.Order, error) {
+	return changeOrderStatus(ctx, s.CancelOrderUseCase.Execute, in)
+}
+
+func (s *OrderService) RefundOrder(ctx context.Context, in *pb.OrderIdRequest) (*pb.Order, error) {
+	return changeOrderStatus(ctx, s.RefundOrderUseCase.Execute, in)
+}
+
+func (s *OrderService) UpdateOrder(ctx context.Context, in *pb.UpdateOrderRequest) (*pb.Order, error) {
+	output, err := s.UpdateOrderUseCase.Execute(ctx, usecase.UpdateOrderInputDTO{
+		ID:       in.Id,
+		Version:  in.Version,
+		Currency: in.Currency,
+		Items:    toItemInputs(in.Items),
+	})
+	if err != nil {
+		return nil, apierror.GRPCError(err)
+	}
+	return toProtoOrder(output), nil
+}
+
+func (s *OrderService) DeleteOrder(ctx context.Context, in *pb.DeleteOrderRequest) (*pb.DeleteOrderResponse, error) {
+	output, err := s.DeleteOrderUseCase.Execute(ctx, usecase.DeleteOrderInputDTO{ID: in.Id, Version: in.Version})
+	if err != nil {
+		return nil, apierror.GRPCError(err)
+	}
+	return &pb.DeleteOrderResponse{Id: output.ID}, nil
+}
+
+func changeOrderStatus(ctx context.Context, execute func(context.Context, usecase.OrderStatusInputDTO) (usecase.OrderOutputDTO, error), in *pb.OrderIdRequest) (*pb.Order, error) {
+	output, err := execute(ctx, usecase.OrderStatusInputDTO{ID: in.Id})
+	if err != nil {
+		return nil, apierror.GRPCError(err)
+	}
+	return toProtoOrder(output), nil
+}
+
+func toOrderInput(ctx context.Context, in *pb.CreateOrderRequest) usecase.OrderInputDTO {
+	return usecase.OrderInputDTO{
+		ID:        in.Id,
+		Currency:  in.Currency,
+		Items:     toItemInputs(in.Items),
+		CreatedBy: auth.Subject(ctx),
+	}
+}
+
+func toCreateOrderResponse(output usecase.OrderOutputDTO) *pb.CreateOrderResponse {
+	return &pb.CreateOrderResponse{
+		Id:         output.ID,
+		Currency:   output.Currency,
+		Items:      toProtoItems(output.Items),
+		Price:      toProtoMoney(output.Price),
+		Tax:        toProtoMoney(output.Tax),
+		FinalPrice: toProtoMoney(output.FinalPrice),
+		Status:     output.Status,
+		CreatedAt:  timestamppb.New(output.CreatedAt),
+		CreatedBy:  output.CreatedBy,
+	}
+}
+
+func toListOrdersInput(in *pb.ListOrdersRequest) usecase.ListOrdersInputDTO {
+	input := usecase.ListOrdersInputDTO{
+		PageSize:   int(in.PageSize),
+		After:      in.After,
+		Status:     in.Status,
+		MinPrice:   in.MinPrice,
+		MaxPrice:   in.MaxPrice,
+		SortBy:     string(entity.OrderSortByCreatedAt),
+		Descending: in.Descending,
+	}
+	if in.SortBy == pb.OrderSortField_ORDER_SORT_FIELD_FINAL_PRICE {
+		input.SortBy = string(entity.OrderSortByFinalPrice)
+	}
+	if in.CreatedFrom != nil {
+		createdFrom := in.CreatedFrom.AsTime()
+		input.CreatedFrom = &createdFrom
+	}
+	if in.CreatedTo != nil {
+		createdTo := in.CreatedTo.AsTime()
+		input.CreatedTo = &createdTo
+	}
+	return input
+}
+
+func toProtoOrder(order usecase.OrderOutputDTO) *pb.Order {
+	return &pb.Order{
+		Id:          order.ID,
+		Currency:    order.Currency,
+		Items:       toProtoItems(order.Items),
+		Price:       toProtoMoney(order.Price),
+		Tax:         toProtoMoney(order.Tax),
+		FinalPrice:  toProtoMoney(order.FinalPrice),
+		Status:      order.Status,
+		CreatedAt:   timestamppb.New(order.CreatedAt),
+		PaidAt:      toProtoTimestamp(order.PaidAt),
+		ShippedAt:   toProtoTimestamp(order.ShippedAt),
+		DeliveredAt: toProtoTimestamp(order.DeliveredAt),
+		CancelledAt: toProtoTimestamp(order.CancelledAt),
+		RefundedAt:  toProtoTimestamp(order.RefundedAt),
+		Version:     order.Version,
+		CreatedBy:   order.CreatedBy,
+	}
+}
+
+func toItemInputs(items []*pb.OrderItemInput) []usecase.OrderItemInputDTO {
+	var result []usecase.OrderItemInputDTO
+	for _, item := range items {
+		result = append(result, usecase.OrderItemInputDTO{
+			SKU:        item.Sku,
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+			TaxRateBps: item.TaxRateBps,
+		})
+	}
+	return result
+}
+
+func toProtoItems(items []usecase.OrderItemOutputDTO) []*pb.OrderItem {
+	var result []*pb.OrderItem
+	for _, item := range items {
+		result = append(result, &pb.OrderItem{
+			Sku:        item.SKU,
+			Quantity:   item.Quantity,
+			UnitPrice:  toProtoMoney(item.UnitPrice),
+			TaxRateBps: item.TaxRateBps,
+			Subtotal:   toProtoMoney(item.Subtotal),
+			Tax:        toProtoMoney(item.Tax),
+		})
+	}
+	return result
+}
+
+func toProtoMoney(m usecase.MoneyDTO) *pb.Money {
+	return &pb.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+func toProtoTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/infra/grpc/pb"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxBulkCreateOrders caps how many orders one BulkCreateOrders call takes.
+const MaxBulkCreateOrders = 1000
+
+// StreamOrders sends every order matching the filters, reading page_size
+// orders from the database at a time, so clients need not page by hand.
+func (s *OrderService) StreamOrders(in *pb.ListOrdersRequest, stream pb.OrderService_StreamOrdersServer) error {
+	input := toListOrdersInput(in)
+	if input.PageSize == 0 {
+		input.PageSize = usecase.MaxOrdersPageSize
+	}
+	for {
+		output, err := s.ListOrdersUseCase.ListOrders(stream.Context(), input)
+		if err != nil {
+			return apierror.GRPCError(err)
+		}
+		for _, edge := range output.Edges {
+			if err := stream.Send(toProtoOrder(edge.Order)); err != nil {
+				return err
+			}
+		}
+		if !output.PageInfo.HasNextPage {
+			return nil
+		}
+		input.After = output.PageInfo.EndCursor
+	}
+}
+
+// WatchOrders pushes order events as they happen until the client goes
+// away. A client that cannot keep up is cut off with ResourceExhausted and
+// should reload the orders it cares about before watching again.
+func (s *OrderService) WatchOrders(in *pb.WatchOrdersRequest, stream pb.OrderService_WatchOrdersServer) error {
+	if s.OrderEvents == nil {
+		return status.Error(codes.Unavailable, "order events are not available")
+	}
+	subscription := s.OrderEvents.Subscribe(func(event events.EventInterface) bool {
+		return (len(in.EventTypes) == 0 || slices.Contains(in.EventTypes, event.GetName())) &&
+			(len(in.OrderIds) == 0 || slices.Contains(in.OrderIds, event.GetMetadata().AggregateID))
+	})
+	defer subscription.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscription.C:
+			if !ok {
+				return watchError(subscription.Err())
+			}
+			if err := stream.Send(toProtoOrderEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// BulkCreateOrders creates each streamed order on its own, so a rejected
+// order does not undo the others, and reports every outcome by position.
+// With an "idempotency-key" metadata, order i is created under "<key>:<i>",
+// so retrying the whole stream replays the orders already created.
+func (s *OrderService) BulkCreateOrders(stream pb.OrderService_BulkCreateOrdersServer) error {
+	ctx := stream.Context()
+	var key string
+	if keys := metadata.ValueFromIncomingContext(ctx, "idempotency-key"); len(keys) > 0 {
+		key = keys[0]
+	}
+
+	response := &pb.BulkCreateOrdersResponse{}
+	for index := 0; ; index++ {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+		if index >= MaxBulkCreateOrders {
+			return status.Errorf(codes.ResourceExhausted, "at most %d orders can be created per call", MaxBulkCreateOrders)
+		}
+
+		dto := toOrderInput(ctx, in)
+		if key != "" {
+			dto.IdempotencyKey = fmt.Sprintf("%s:%d", key, index)
+		}
+		result := &pb.BulkCreateOrderResult{Index: int32(index)}
+		output, err := s.CreateOrderUseCase.Execute(ctx, dto)
+		if err != nil {
+			st := status.Convert(apierror.GRPCError(err))
+			result.Outcome = &pb.BulkCreateOrderResult_Error{Error: &pb.BulkCreateOrderError{
+				Code:    int32(st.Code()),
+				Message: st.Message(),
+			}}
+			response.Failed++
+		} else {
+			result.Outcome = &pb.BulkCreateOrderResult_Order{Order: toCreateOrderResponse(output)}
+			response.Created++
+		}
+		response.Results = append(response.Results, result)
+	}
+}
+
+func watchError(err error) error {
+	switch {
+	case errors.Is(err, events.ErrSubscriberTooSlow):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, events.ErrBroadcasterClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return nil
+	}
+}
+
+func toProtoOrderEvent(event events.EventInterface) *pb.OrderEvent {
+	metadata := event.GetMetadata()
+	result := &pb.OrderEvent{
+		EventId:    metadata.ID,
+		EventType:  event.GetName(),
+		OrderId:    metadata.AggregateID,
+		OccurredAt: timestamppb.New(metadata.OccurredAt),
+	}
+	if order, ok := event.GetPayload().(usecase.OrderOutputDTO); ok {
+		result.Order = toProtoOrder(order)
+	}
+	return result
+}
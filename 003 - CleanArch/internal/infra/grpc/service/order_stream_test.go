@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/event"
+	"CleanArch/internal/infra/database"
+	"CleanArch/internal/infra/database/dialect"
+	"CleanArch/internal/infra/database/migrations"
+	"CleanArch/internal/infra/grpc/pb"
+	"CleanArch/internal/usecase"
+	"CleanArch/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	// sqlite
+	_ "modernc.org/sqlite"
+)
+
+// dial serves s over an in-memory listener and returns a client for it.
+func dial(t *testing.T, s *OrderService) pb.OrderServiceClient {
+	t.Helper()
+	return pb.NewOrderServiceClient(connect(t, func(server *grpc.Server) {
+		pb.RegisterOrderServiceServer(server, s)
+	}))
+}
+
+// connect starts a server with options, whose services register adds, over
+// an in-memory listener and returns a connection to it.
+func connect(t *testing.T, register func(*grpc.Server), options ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(options...)
+	register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// newOrderRepository returns a repository on a migrated in-memory SQLite
+// database holding orders a0 to a<n-1>.
+func newOrderRepository(t *testing.T, n int) entity.OrderRepositoryInterface {
+	t.Helper()
+	db, err := sql.Open(dialect.SQLite.DriverName(), ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrations.NewMigrator(db, dialect.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	repository := database.NewOrderRepository(db, dialect.SQLite)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("a%d", i), "BRL", []entity.OrderItem{
+			{SKU: "SKU-1", Quantity: 1, UnitPrice: entity.Money{Amount: 1000, Currency: "BRL"}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, order.CalculateFinalPrice())
+		order.CreatedAt = createdAt.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repository.Save(context.Background(), order))
+	}
+	return repository
+}
+
+func TestGivenMoreOrdersThanAPage_WhenStreamed_ThenShouldSendThemAll(t *testing.T) {
+	client := dial(t, &OrderService{ListOrdersUseCase: *usecase.NewListOrdersUseCase(newOrderRepository(t, 5))})
+
+	stream, err := client.StreamOrders(context.Background(), &pb.ListOrdersRequest{PageSize: 2})
+	require.NoError(t, err)
+
+	var ids []string
+	for {
+		order, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, order.Id)
+	}
+	assert.ElementsMatch(t, []string{"a0", "a1", "a2", "a3", "a4"}, ids)
+}
+
+// watch starts WatchOrders and waits until it is subscribed.
+func watch(t *testing.T, broadcaster *events.Broadcaster, in *pb.WatchOrdersRequest) pb.OrderService_WatchOrdersClient {
+	t.Helper()
+	client := dial(t, &OrderService{OrderEvents: broadcaster})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream, err := client.WatchOrders(ctx, in)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return broadcaster.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+	return stream
+}
+
+func orderEvent(newEvent events.EventFactory, id, status string) events.EventInterface {
+	e := newEvent()
+	e.SetPayload(usecase.OrderOutputDTO{ID: id, Status: status})
+	e.SetMetadata(events.NewMetadata(context.Background(), id))
+	return e
+}
+
+func TestGivenAWatcher_WhenOrdersChange_ThenShouldReceiveTheMatchingEvents(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	stream := watch(t, broadcaster, &pb.WatchOrdersRequest{EventTypes: []string{"OrderPaid"}, OrderIds: []string{"a"}})
+
+	broadcaster.Handle(context.Background(), orderEvent(event.NewOrderPaidFactory(), "b", "paid"))
+	broadcaster.Handle(context.Background(), orderEvent(event.NewOrderShippedFactory(), "a", "shipped"))
+	paid := orderEvent(event.NewOrderPaidFactory(), "a", "paid")
+	broadcaster.Handle(context.Background(), paid)
+
+	received, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, paid.GetMetadata().ID, received.EventId)
+	assert.Equal(t, "OrderPaid", received.EventType)
+	assert.Equal(t, "a", received.OrderId)
+	assert.Equal(t, "paid", received.Order.Status)
+}
+
+func TestGivenAWatcherThatFallsBehind_WhenEventsPileUp_ThenShouldBeCutOffWithResourceExhausted(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	broadcaster.Buffer = 1
+	stream := watch(t, broadcaster, &pb.WatchOrdersRequest{})
+
+	// the client reads nothing, so the server soon blocks on flow control
+	// and the subscription buffer overflows
+	deadline := time.Now().Add(5 * time.Second)
+	for broadcaster.Subscribers() > 0 {
+		require.True(t, time.Now().Before(deadline), "the watcher was never cut off")
+		broadcaster.Handle(context.Background(), orderEvent(event.NewOrderPaidFactory(), "a", "paid"))
+	}
+
+	var err error
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, err.Error(), events.ErrSubscriberTooSlow.Error())
+}
+
+func TestGivenNoOrderEvents_WhenWatched_ThenShouldBeUnavailable(t *testing.T) {
+	stream, err := dial(t, &OrderService{}).WatchOrders(context.Background(), &pb.WatchOrdersRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
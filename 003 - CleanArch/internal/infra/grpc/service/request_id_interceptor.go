@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"CleanArch/pkg/events"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDKey is the metadata key of the request ID, the gRPC counterpart
+// of the X-Request-Id HTTP header.
+const requestIDKey = "x-request-id"
+
+// RequestIDUnaryInterceptor keeps the client's "x-request-id" metadata or
+// generates one, returns it in the response header and correlates the events
+// the call raises with it.
+func RequestIDUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+// RequestIDStreamInterceptor is RequestIDUnaryInterceptor for streaming
+// methods.
+func RequestIDStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: stream, ctx: withRequestID(stream.Context())})
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	id := uuid.NewString()
+	if values := metadata.ValueFromIncomingContext(ctx, requestIDKey); len(values) > 0 && values[0] != "" {
+		id = values[0]
+	}
+	// fails only outside a server call, where there is no header to set
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	return events.WithCorrelationID(ctx, id)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"CleanArch/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func correlationIDOf(ctx context.Context) string {
+	var id string
+	RequestIDUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		id = events.CorrelationID(ctx)
+		return nil, nil
+	})
+	return id
+}
+
+func TestGivenARequestIDInMetadata_WhenIntercepted_ThenShouldCorrelateTheCallWithIt(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "abc-123"))
+
+	assert.Equal(t, "abc-123", correlationIDOf(ctx))
+}
+
+func TestGivenNoRequestID_WhenIntercepted_ThenShouldGenerateOne(t *testing.T) {
+	first := correlationIDOf(context.Background())
+	second := correlationIDOf(context.Background())
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
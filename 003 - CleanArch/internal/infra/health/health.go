@@ -0,0 +1,183 @@
+// Package health reports whether the process is alive and ready to take
+// traffic, over HTTP and through the gRPC health service.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// Check returns nil when the dependency it probes is usable.
+type Check func(ctx context.Context) error
+
+// Pinger is implemented by the message broker publishers.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Database checks that db answers a ping.
+func Database(db *sql.DB) Check {
+	return db.PingContext
+}
+
+// Report is the body of /healthz and /readyz. Checks maps each check name to
+// "ok" or "unavailable"; the error itself is only logged, as the endpoint is
+// public.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Checker runs the readiness checks. Each one gets Timeout; they run
+// concurrently, so /readyz answers within Timeout even when a dependency
+// hangs. Failed checks are logged to Logger, or the standard logger when it
+// is nil.
+type Checker struct {
+	Timeout time.Duration
+	Logger  *log.Logger
+
+	mu       sync.Mutex
+	checks   map[string]Check
+	draining atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{
+		Timeout: 2 * time.Second,
+		checks:  make(map[string]Check),
+	}
+}
+
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Drain makes the process report itself not ready from now on, so load
+// balancers stop sending traffic while it shuts down.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Ready runs every check and reports whether all of them passed.
+func (c *Checker) Ready(ctx context.Context) (Report, bool) {
+	c.mu.Lock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.Unlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]string, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := StatusOK
+			if err := c.run(ctx, check); err != nil {
+				c.logger().Printf("health: %s check failed: %v", name, err)
+				result = StatusUnavailable
+			}
+			mu.Lock()
+			report.Checks[name] = result
+			if result != StatusOK {
+				report.Status = StatusUnavailable
+			}
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	if c.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report, report.Status == StatusOK
+}
+
+func (c *Checker) logger() *log.Logger {
+	if c.Logger == nil {
+		return log.Default()
+	}
+	return c.Logger
+}
+
+// run gives up on check once its timeout passes, leaving it to finish in
+// the background.
+func (c *Checker) run(ctx context.Context, check Check) error {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.New("timed out")
+	}
+}
+
+// Live serves /healthz: the process is up and serving HTTP, whatever the
+// state of its dependencies.
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, Report{Status: StatusOK})
+}
+
+// ReadyHandler serves /readyz: 200 when every check passes, 503 otherwise or
+// while draining.
+func (c *Checker) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	report, ok := c.Ready(r.Context())
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+func writeReport(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// Watch keeps the overall ("") status of the gRPC health server in step with
+// Ready, checking every interval until ctx ends. Calling server.Shutdown when
+// draining pins it to NOT_SERVING.
+func (c *Checker) Watch(ctx context.Context, server *grpchealth.Server, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if _, ok := c.Ready(ctx); ok {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		server.SetServingStatus("", status)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
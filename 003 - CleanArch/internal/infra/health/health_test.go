@@ -0,0 +1,90 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ready(t *testing.T, checker *Checker) (int, Report) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	checker.ReadyHandler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report Report
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+	return recorder.Code, report
+}
+
+func passing(context.Context) error { return nil }
+
+func TestGivenPassingChecks_WhenReady_ThenShouldReturn200(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("database", passing)
+	checker.Add("broker", passing)
+
+	code, report := ready(t, checker)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, Report{Status: StatusOK, Checks: map[string]string{"database": "ok", "broker": "ok"}}, report)
+}
+
+func TestGivenAFailingCheck_WhenReady_ThenShouldReturn503AndOnlyLogItsError(t *testing.T) {
+	checker := NewChecker()
+	var logs bytes.Buffer
+	checker.Logger = log.New(&logs, "", 0)
+	checker.Add("database", passing)
+	checker.Add("broker", func(context.Context) error { return errors.New("dial tcp 10.0.0.7:5672: not connected") })
+
+	code, report := ready(t, checker)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, StatusUnavailable, report.Checks["broker"])
+	assert.Equal(t, "ok", report.Checks["database"])
+	assert.Equal(t, "health: broker check failed: dial tcp 10.0.0.7:5672: not connected\n", logs.String())
+}
+
+func TestGivenAHangingCheck_WhenReady_ThenShouldTimeOut(t *testing.T) {
+	checker := NewChecker()
+	checker.Timeout = 20 * time.Millisecond
+	var logs bytes.Buffer
+	checker.Logger = log.New(&logs, "", 0)
+	release := make(chan struct{})
+	defer close(release)
+	checker.Add("database", func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	code, report := ready(t, checker)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusUnavailable, report.Checks["database"])
+	assert.Contains(t, logs.String(), "health: database check failed: timed out")
+}
+
+func TestGivenADrainingChecker_WhenReadyAndLive_ThenOnlyReadyShouldFail(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("database", passing)
+	checker.Drain()
+
+	code, report := ready(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusDraining, report.Status)
+
+	recorder := httptest.NewRecorder()
+	checker.Live(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+}
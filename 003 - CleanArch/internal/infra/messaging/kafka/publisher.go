@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"CleanArch/pkg/events"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+type Config struct {
+	Brokers []string
+	Topic   string
+}
+
+// Publisher writes every event to a single topic, keyed by aggregate so the
+// events of one order land on the same partition and stay ordered. The event
+// name travels in the "event_name" header.
+type Publisher struct {
+	writer  *kafkago.Writer
+	brokers []string
+}
+
+func NewPublisher(config Config) *Publisher {
+	return &Publisher{
+		writer: &kafkago.Writer{
+			Addr:                   kafkago.TCP(config.Brokers...),
+			Topic:                  config.Topic,
+			Balancer:               &kafkago.Hash{},
+			RequiredAcks:           kafkago.RequireAll,
+			AllowAutoTopicCreation: true,
+			BatchTimeout:           10 * time.Millisecond,
+		},
+		brokers: config.Brokers,
+	}
+}
+
+// ParseBrokers splits a comma-separated broker list such as
+// "kafka-1:9092,kafka-2:9092".
+func ParseBrokers(value string) []string {
+	var brokers []string
+	for _, broker := range strings.Split(value, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	return brokers
+}
+
+func (p *Publisher) Publish(ctx context.Context, message events.Message) error {
+	headers := []kafkago.Header{
+		{Key: "event_id", Value: []byte(message.ID)},
+		{Key: "event_name", Value: []byte(message.Name)},
+		{Key: "content-type", Value: []byte(message.ContentType)},
+	}
+	for k, v := range message.Headers {
+		headers = append(headers, kafkago.Header{Key: k, Value: []byte(v)})
+	}
+	return p.writer.WriteMessages(ctx, kafkago.Message{
+		Key:     []byte(message.Key),
+		Value:   message.Payload,
+		Headers: headers,
+		Time:    message.Timestamp,
+	})
+}
+
+// Ping succeeds as soon as one of the brokers accepts a connection.
+func (p *Publisher) Ping(ctx context.Context) error {
+	err := errors.New("kafka: no brokers configured")
+	for _, broker := range p.brokers {
+		var conn *kafkago.Conn
+		if conn, err = kafkago.DialContext(ctx, "tcp", broker); err == nil {
+			return conn.Close()
+		}
+	}
+	return err
+}
+
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
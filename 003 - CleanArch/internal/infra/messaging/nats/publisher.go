@@ -0,0 +1,74 @@
+package nats
+
+import (
+	"context"
+	"errors"
+
+	"CleanArch/pkg/events"
+
+	natsgo "github.com/nats-io/nats.go"
+)
+
+type Config struct {
+	URL           string
+	Stream        string
+	SubjectPrefix string
+}
+
+// Publisher publishes to JetStream on "<prefix>.<routing key>", e.g.
+// "orders.order.created". JetStream acknowledges each message and drops
+// duplicates by message ID within the stream's dedupe window.
+type Publisher struct {
+	conn   *natsgo.Conn
+	js     natsgo.JetStreamContext
+	prefix string
+}
+
+// NewPublisher connects with unlimited reconnects and creates the stream if
+// it does not exist yet.
+func NewPublisher(config Config) (*Publisher, error) {
+	conn, err := natsgo.Connect(config.URL, natsgo.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_, err = js.StreamInfo(config.Stream)
+	if errors.Is(err, natsgo.ErrStreamNotFound) {
+		_, err = js.AddStream(&natsgo.StreamConfig{
+			Name:     config.Stream,
+			Subjects: []string{config.SubjectPrefix + ".>"},
+			Storage:  natsgo.FileStorage,
+		})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Publisher{conn: conn, js: js, prefix: config.SubjectPrefix}, nil
+}
+
+func (p *Publisher) Publish(ctx context.Context, message events.Message) error {
+	msg := natsgo.NewMsg(p.prefix + "." + events.RoutingKey(message.Name))
+	msg.Data = message.Payload
+	msg.Header.Set("event_name", message.Name)
+	msg.Header.Set("aggregate_id", message.Key)
+	msg.Header.Set("Content-Type", message.ContentType)
+	for k, v := range message.Headers {
+		msg.Header.Set(k, v)
+	}
+	_, err := p.js.PublishMsg(msg, natsgo.MsgId(message.ID), natsgo.Context(ctx))
+	return err
+}
+
+// Ping makes a round trip to the server.
+func (p *Publisher) Ping(ctx context.Context) error {
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *Publisher) Close() error {
+	return p.conn.Drain()
+}
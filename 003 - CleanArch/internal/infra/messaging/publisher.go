@@ -0,0 +1,40 @@
+package messaging
+
+import (
+	"fmt"
+
+	"CleanArch/internal/infra/messaging/kafka"
+	"CleanArch/internal/infra/messaging/nats"
+	"CleanArch/internal/infra/messaging/rabbitmq"
+	"CleanArch/pkg/events"
+)
+
+const (
+	BrokerRabbitMQ = "rabbitmq"
+	BrokerKafka    = "kafka"
+	BrokerNATS     = "nats"
+	BrokerMemory   = "memory"
+)
+
+type Config struct {
+	Broker   string
+	RabbitMQ rabbitmq.Config
+	Kafka    kafka.Config
+	NATS     nats.Config
+}
+
+// NewPublisher returns the events.Publisher adapter for config.Broker.
+func NewPublisher(config Config) (events.Publisher, error) {
+	switch config.Broker {
+	case BrokerRabbitMQ, "":
+		return rabbitmq.NewPublisher(config.RabbitMQ), nil
+	case BrokerKafka:
+		return kafka.NewPublisher(config.Kafka), nil
+	case BrokerNATS:
+		return nats.NewPublisher(config.NATS)
+	case BrokerMemory:
+		return events.NewInMemoryPublisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown message broker %q", config.Broker)
+	}
+}
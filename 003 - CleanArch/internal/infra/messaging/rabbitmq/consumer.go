@@ -0,0 +1,326 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"CleanArch/internal/infra/consumer"
+	"CleanArch/pkg/events"
+
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// attemptHeader counts deliveries of a message across retries.
+	attemptHeader = "x-attempt"
+	// routingKeyHeader keeps the key a message was first published with, as
+	// coming back from the retry queue replaces it with the queue name.
+	routingKeyHeader = "x-routing-key"
+	consumerTag      = "orders-consumer"
+)
+
+type Processor interface {
+	Process(ctx context.Context, message events.Message, attempt int) consumer.Outcome
+}
+
+type ConsumerConfig struct {
+	URL         string
+	Topology    Topology
+	Queue       string
+	Concurrency int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// ConfirmTimeout bounds the wait for the broker to confirm a retry or
+	// dead letter before the original delivery is acked.
+	ConfirmTimeout time.Duration
+	// DrainTimeout is how long messages already being processed may keep
+	// running once Run's context is cancelled.
+	DrainTimeout time.Duration
+}
+
+// Consumer feeds a queue to a Processor with up to Concurrency messages in
+// flight. Retries are republished to the queue's retry queue with the attempt
+// count in a header; dead letters are republished to the dead-letter
+// exchange with their original routing key, so they reach the dead-letter
+// queue whatever attempt they fail on. The original is acked only once the
+// broker confirms the republished copy.
+type Consumer struct {
+	config    ConsumerConfig
+	processor Processor
+}
+
+func NewConsumer(config ConsumerConfig, processor Processor) *Consumer {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 30 * time.Second
+	}
+	if config.ConfirmTimeout <= 0 {
+		config.ConfirmTimeout = 5 * time.Second
+	}
+	if config.DrainTimeout <= 0 {
+		config.DrainTimeout = 30 * time.Second
+	}
+	return &Consumer{config: config, processor: processor}
+}
+
+// Run consumes until ctx is cancelled, reconnecting with backoff when the
+// connection drops. In-flight messages are finished, within DrainTimeout,
+// before it returns; prefetched ones are requeued untouched.
+func (c *Consumer) Run(ctx context.Context) {
+	backoff := c.config.MinBackoff
+	for ctx.Err() == nil {
+		err := c.consume(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("rabbitmq consumer: %v, reconnecting in %s", err, backoff)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if err != nil {
+			backoff *= 2
+			if backoff > c.config.MaxBackoff {
+				backoff = c.config.MaxBackoff
+			}
+		} else {
+			backoff = c.config.MinBackoff
+		}
+	}
+}
+
+func (c *Consumer) consume(ctx context.Context) error {
+	conn, err := amqp.Dial(c.config.URL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	if err := c.config.Topology.Declare(ch); err != nil {
+		return err
+	}
+	if err := ch.Qos(c.config.Concurrency, 0, false); err != nil {
+		return err
+	}
+	if err := ch.Confirm(false); err != nil {
+		return err
+	}
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	deliveries, err := ch.Consume(c.config.Queue, consumerTag, false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	forward := confirmed(func(exchange, key string, msg amqp.Publishing) error {
+		return ch.Publish(exchange, key, false, false, msg)
+	}, confirms, c.config.ConfirmTimeout)
+	publish := func(exchange, key string, msg amqp.Publishing) error {
+		err := forward(exchange, key, msg)
+		if errors.Is(err, ErrConfirmTimeout) {
+			// delivery tags can no longer be matched to publishes; start over
+			conn.Close()
+		}
+		return err
+	}
+	work, stopWork := c.workContext(ctx)
+	defer stopWork()
+	var mu sync.Mutex // amqp channels are not safe for concurrent publishes
+	var wg sync.WaitGroup
+	for i := 0; i < c.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.work(ctx, work, deliveries, publish, &mu)
+		}()
+	}
+
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	select {
+	case <-ctx.Done():
+		// stop new deliveries and let the workers ack what they hold
+		ch.Cancel(consumerTag, false)
+		wg.Wait()
+		return nil
+	case err := <-closed:
+		wg.Wait()
+		return err
+	}
+}
+
+// publishFunc is amqp.Channel.Publish, neither mandatory nor immediate.
+type publishFunc func(exchange, key string, msg amqp.Publishing) error
+
+// confirmed makes publish wait for the broker to confirm each message. The
+// publishes must be serialized, as the workers' mutex does, so that each
+// confirm belongs to the message just sent.
+func confirmed(publish publishFunc, confirms <-chan amqp.Confirmation, timeout time.Duration) publishFunc {
+	return func(exchange, key string, msg amqp.Publishing) error {
+		if err := publish(exchange, key, msg); err != nil {
+			return err
+		}
+		select {
+		case confirm, ok := <-confirms:
+			if !ok {
+				return ErrNotConnected
+			}
+			if !confirm.Ack {
+				return ErrNacked
+			}
+			return nil
+		case <-time.After(timeout):
+			return ErrConfirmTimeout
+		}
+	}
+}
+
+// workContext outlives ctx by DrainTimeout, so a message being processed at
+// shutdown can commit instead of failing and using up a retry.
+func (c *Consumer) workContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	work, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(c.config.DrainTimeout, cancel)
+	})
+	return work, func() {
+		stop()
+		cancel()
+	}
+}
+
+// work handles deliveries under the work context until the channel closes.
+// Once ctx is cancelled, the ones still arriving were prefetched and are
+// handed back to the broker unprocessed.
+func (c *Consumer) work(ctx, work context.Context, deliveries <-chan amqp.Delivery, publish publishFunc, mu *sync.Mutex) {
+	for d := range deliveries {
+		if ctx.Err() != nil {
+			mu.Lock()
+			err := d.Nack(false, true)
+			mu.Unlock()
+			if err != nil {
+				log.Printf("rabbitmq consumer: requeue %s: %v", d.MessageId, err)
+			}
+			continue
+		}
+		c.handle(work, publish, mu, d)
+	}
+}
+
+func (c *Consumer) handle(ctx context.Context, publish publishFunc, mu *sync.Mutex, d amqp.Delivery) {
+	attempt := deliveryAttempt(d)
+	message := toEventMessage(d)
+
+	// continue the trace the publisher injected into the headers
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(message.Headers))
+	ctx, span := otel.Tracer("CleanArch/internal/infra/messaging/rabbitmq").Start(ctx, message.Name+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(c.config.Queue),
+			semconv.MessagingMessageID(message.ID),
+			attribute.Int("messaging.rabbitmq.attempt", attempt),
+		))
+	defer span.End()
+
+	outcome := c.processor.Process(ctx, message, attempt)
+	span.SetAttributes(attribute.String("messaging.outcome", outcome.String()))
+	if outcome != consumer.Ack {
+		span.SetStatus(codes.Error, outcome.String())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var err error
+	switch outcome {
+	case consumer.Ack:
+		err = d.Ack(false)
+	case consumer.Retry, consumer.DeadLetter:
+		exchange, key, publishing := c.forward(d, outcome, attempt)
+		err = publish(exchange, key, publishing)
+		if err == nil {
+			err = d.Ack(false)
+		} else {
+			// the copy may not have been stored; let the broker redeliver it
+			err = d.Nack(false, true)
+		}
+	}
+	if err != nil {
+		log.Printf("rabbitmq consumer: %s %s: %v", outcome, d.MessageId, err)
+	}
+}
+
+// forward says where a message that was not acked goes next: the retry
+// queue, through the default exchange, with the attempt count bumped, or the
+// dead-letter exchange under the routing key it was first published with.
+func (c *Consumer) forward(d amqp.Delivery, outcome consumer.Outcome, attempt int) (string, string, amqp.Publishing) {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	key, ok := headers[routingKeyHeader].(string)
+	if !ok {
+		key = d.RoutingKey
+		headers[routingKeyHeader] = key
+	}
+	publishing := amqp.Publishing{
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Type:         d.Type,
+		Timestamp:    d.Timestamp,
+		Headers:      headers,
+		Body:         d.Body,
+	}
+	if outcome == consumer.DeadLetter {
+		return c.config.Topology.DeadLetterExchange, key, publishing
+	}
+	headers[attemptHeader] = int32(attempt + 1)
+	return "", RetryQueueName(c.config.Queue), publishing
+}
+
+func deliveryAttempt(d amqp.Delivery) int {
+	switch v := d.Headers[attemptHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	}
+	return 1
+}
+
+func toEventMessage(d amqp.Delivery) events.Message {
+	headers := make(map[string]string)
+	for k, v := range d.Headers {
+		if s, ok := v.(string); ok {
+			headers[k] = s
+		}
+	}
+	return events.Message{
+		ID:          d.MessageId,
+		Name:        d.Type,
+		Key:         headers["aggregate_id"],
+		ContentType: d.ContentType,
+		Payload:     d.Body,
+		Timestamp:   d.Timestamp,
+		Headers:     headers,
+	}
+}
@@ -0,0 +1,245 @@
+package rabbitmq
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"CleanArch/internal/infra/consumer"
+	"CleanArch/pkg/events"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// topicMatches applies the AMQP topic exchange rules: "*" matches one word
+// and "#" zero or more.
+func topicMatches(pattern, key string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(key, "."))
+}
+
+func matchWords(pattern, key []string) bool {
+	if len(pattern) == 0 {
+		return len(key) == 0
+	}
+	if pattern[0] == "#" {
+		for i := 0; i <= len(key); i++ {
+			if matchWords(pattern[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(key) == 0 || (pattern[0] != "*" && pattern[0] != key[0]) {
+		return false
+	}
+	return matchWords(pattern[1:], key[1:])
+}
+
+// deadLetterQueues returns the dead-letter queues a message published to
+// the dead-letter exchange with key reaches.
+func deadLetterQueues(topology Topology, key string) []string {
+	var queues []string
+	for _, q := range topology.Queues {
+		for _, binding := range q.BindingKeys {
+			if topicMatches(binding, key) {
+				queues = append(queues, DeadLetterQueueName(q.Name))
+				break
+			}
+		}
+	}
+	return queues
+}
+
+func TestGivenAMessageThatKeepsFailing_WhenItRunsOutOfAttempts_ThenShouldReachTheDeadLetterQueue(t *testing.T) {
+	const maxAttempts = 3
+	topology := DefaultTopology()
+	c := NewConsumer(ConsumerConfig{Topology: topology, Queue: "orders.events"}, nil)
+	d := amqp.Delivery{
+		Exchange:   topology.Exchange,
+		RoutingKey: "order.created",
+		MessageId:  "event-1",
+		Type:       "OrderCreated",
+		Headers:    amqp.Table{"aggregate_id": "order-1"},
+		Body:       []byte(`{}`),
+	}
+
+	var exchange, key string
+	var publishing amqp.Publishing
+	for attempt := 1; ; attempt++ {
+		require.Equal(t, attempt, deliveryAttempt(d))
+		outcome := consumer.Retry
+		if attempt >= maxAttempts {
+			outcome = consumer.DeadLetter
+		}
+		exchange, key, publishing = c.forward(d, outcome, attempt)
+		if outcome == consumer.DeadLetter {
+			break
+		}
+		assert.Equal(t, "", exchange)
+		assert.Equal(t, "orders.events.retry", key)
+		// the retry queue dead-letters it back under the work queue's name
+		d = amqp.Delivery{
+			RoutingKey: "orders.events",
+			MessageId:  publishing.MessageId,
+			Type:       publishing.Type,
+			Headers:    publishing.Headers,
+			Body:       publishing.Body,
+		}
+	}
+
+	assert.Equal(t, topology.DeadLetterExchange, exchange)
+	assert.Equal(t, "order.created", key)
+	assert.Equal(t, []string{"orders.events.dlq"}, deadLetterQueues(topology, key))
+	assert.Equal(t, "event-1", publishing.MessageId)
+	assert.Equal(t, "order-1", publishing.Headers["aggregate_id"])
+	assert.Equal(t, int32(maxAttempts), publishing.Headers[attemptHeader])
+}
+
+func TestGivenAMessageThatFailsPermanently_WhenFirstDelivered_ThenShouldKeepItsRoutingKey(t *testing.T) {
+	topology := DefaultTopology()
+	c := NewConsumer(ConsumerConfig{Topology: topology, Queue: "orders.events"}, nil)
+	d := amqp.Delivery{Exchange: topology.Exchange, RoutingKey: "order.paid", MessageId: "event-2"}
+
+	exchange, key, _ := c.forward(d, consumer.DeadLetter, 1)
+
+	assert.Equal(t, topology.DeadLetterExchange, exchange)
+	assert.Equal(t, []string{"orders.events.dlq"}, deadLetterQueues(topology, key))
+}
+
+// recordingAcknowledger notes what the consumer did with each delivery tag.
+type recordingAcknowledger struct {
+	mu      sync.Mutex
+	acked   []uint64
+	requeue []uint64
+}
+
+func (a *recordingAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.acked = append(a.acked, tag)
+	return nil
+}
+
+func (a *recordingAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if requeue {
+		a.requeue = append(a.requeue, tag)
+	}
+	return nil
+}
+
+func (a *recordingAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// blockingProcessor holds the first message until released and records
+// whether its context was still usable by then.
+type blockingProcessor struct {
+	started  chan struct{}
+	release  chan struct{}
+	ctxErr   error
+	messages []string
+}
+
+func (p *blockingProcessor) Process(ctx context.Context, message events.Message, attempt int) consumer.Outcome {
+	p.messages = append(p.messages, message.ID)
+	close(p.started)
+	<-p.release
+	p.ctxErr = ctx.Err()
+	return consumer.Ack
+}
+
+func TestGivenAShutdownWhileProcessing_WhenWorking_ThenShouldFinishInFlightAndRequeueTheRest(t *testing.T) {
+	processor := &blockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	c := NewConsumer(ConsumerConfig{Topology: DefaultTopology(), Queue: "orders.events"}, processor)
+	acknowledger := &recordingAcknowledger{}
+	deliveries := make(chan amqp.Delivery, 2)
+	deliveries <- amqp.Delivery{Acknowledger: acknowledger, DeliveryTag: 1, MessageId: "in-flight"}
+	deliveries <- amqp.Delivery{Acknowledger: acknowledger, DeliveryTag: 2, MessageId: "prefetched"}
+	close(deliveries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	work, stopWork := c.workContext(ctx)
+	defer stopWork()
+	done := make(chan struct{})
+	go func() {
+		c.work(ctx, work, deliveries, func(string, string, amqp.Publishing) error { return nil }, &sync.Mutex{})
+		close(done)
+	}()
+
+	<-processor.started
+	cancel()
+	close(processor.release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker did not stop")
+	}
+
+	assert.NoError(t, processor.ctxErr)
+	assert.Equal(t, []string{"in-flight"}, processor.messages)
+	assert.Equal(t, []uint64{1}, acknowledger.acked)
+	assert.Equal(t, []uint64{2}, acknowledger.requeue)
+}
+
+func TestGivenAShutdown_WhenTheDrainTimeoutPasses_ThenShouldCancelTheWork(t *testing.T) {
+	c := NewConsumer(ConsumerConfig{DrainTimeout: 50 * time.Millisecond}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	work, stopWork := c.workContext(ctx)
+	defer stopWork()
+
+	cancel()
+	assert.NoError(t, work.Err())
+
+	select {
+	case <-work.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("work context was not cancelled after the drain timeout")
+	}
+}
+
+func TestGivenABrokerConfirm_WhenPublishingConfirmed_ThenShouldReportItsOutcome(t *testing.T) {
+	publish := func(string, string, amqp.Publishing) error { return nil }
+	confirms := make(chan amqp.Confirmation, 1)
+	forward := confirmed(publish, confirms, 50*time.Millisecond)
+
+	confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+	assert.NoError(t, forward("", "orders.events.retry", amqp.Publishing{}))
+
+	confirms <- amqp.Confirmation{DeliveryTag: 2, Ack: false}
+	assert.ErrorIs(t, forward("", "orders.events.retry", amqp.Publishing{}), ErrNacked)
+
+	assert.ErrorIs(t, forward("", "orders.events.retry", amqp.Publishing{}), ErrConfirmTimeout)
+
+	close(confirms)
+	assert.ErrorIs(t, forward("", "orders.events.retry", amqp.Publishing{}), ErrNotConnected)
+}
+
+// outcomeProcessor returns the same outcome for every message.
+type outcomeProcessor consumer.Outcome
+
+func (p outcomeProcessor) Process(context.Context, events.Message, int) consumer.Outcome {
+	return consumer.Outcome(p)
+}
+
+func TestGivenARetryTheBrokerDoesNotConfirm_WhenHandled_ThenShouldRequeueTheOriginal(t *testing.T) {
+	for _, outcome := range []consumer.Outcome{consumer.Retry, consumer.DeadLetter} {
+		t.Run(outcome.String(), func(t *testing.T) {
+			c := NewConsumer(ConsumerConfig{Topology: DefaultTopology(), Queue: "orders.events"}, outcomeProcessor(outcome))
+			acknowledger := &recordingAcknowledger{}
+			confirms := make(chan amqp.Confirmation, 1)
+			confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: false}
+			publish := confirmed(func(string, string, amqp.Publishing) error { return nil }, confirms, 50*time.Millisecond)
+
+			c.handle(context.Background(), publish, &sync.Mutex{}, amqp.Delivery{Acknowledger: acknowledger, DeliveryTag: 7, MessageId: "event-1"})
+
+			assert.Empty(t, acknowledger.acked)
+			assert.Equal(t, []uint64{7}, acknowledger.requeue)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"CleanArch/pkg/events"
+
+	"github.com/streadway/amqp"
+)
+
+var (
+	ErrNotConnected   = errors.New("rabbitmq: not connected")
+	ErrNacked         = errors.New("rabbitmq: message nacked by broker")
+	ErrUnroutable     = errors.New("rabbitmq: message returned as unroutable")
+	ErrConfirmTimeout = errors.New("rabbitmq: timed out waiting for publisher confirm")
+	ErrClosed         = errors.New("rabbitmq: publisher closed")
+)
+
+type Config struct {
+	URL            string
+	Topology       Topology
+	MinBackoff     time.Duration
+	MaxBackoff     time.Duration
+	ConfirmTimeout time.Duration
+}
+
+// Publisher publishes to a topic exchange with publisher confirms. It keeps
+// one connection open and reconnects with exponential backoff whenever the
+// connection or channel is closed. Publishes are serialized so each one can
+// wait for its own confirm.
+type Publisher struct {
+	config Config
+
+	mu       sync.Mutex
+	conn     *amqp.Connection
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+
+	done chan struct{}
+	once sync.Once
+}
+
+// NewPublisher starts connecting in the background and returns immediately;
+// Publish fails with ErrNotConnected until the first connection is up.
+func NewPublisher(config Config) *Publisher {
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 30 * time.Second
+	}
+	if config.ConfirmTimeout <= 0 {
+		config.ConfirmTimeout = 5 * time.Second
+	}
+	p := &Publisher{
+		config: config,
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *Publisher) run() {
+	backoff := p.config.MinBackoff
+	for {
+		connClosed, chClosed, err := p.connect()
+		if err != nil {
+			log.Printf("rabbitmq: connect failed, retrying in %s: %v", backoff, err)
+			select {
+			case <-p.done:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > p.config.MaxBackoff {
+				backoff = p.config.MaxBackoff
+			}
+			continue
+		}
+		backoff = p.config.MinBackoff
+
+		select {
+		case <-p.done:
+			return
+		case err := <-connClosed:
+			log.Printf("rabbitmq: connection lost: %v", err)
+			p.reset()
+		case err := <-chClosed:
+			log.Printf("rabbitmq: channel closed: %v", err)
+			p.reset()
+		}
+	}
+}
+
+// connect dials, enables confirms and declares the topology. The returned
+// channels fire when the connection or the channel closes.
+func (p *Publisher) connect() (<-chan *amqp.Error, <-chan *amqp.Error, error) {
+	conn, err := amqp.Dial(p.config.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if err := p.config.Topology.Declare(ch); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	p.mu.Lock()
+	p.conn = conn
+	p.ch = ch
+	p.confirms = ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	p.returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	p.mu.Unlock()
+	return connClosed, chClosed, nil
+}
+
+func (p *Publisher) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn = nil
+	p.ch = nil
+}
+
+// Publish sends message with a routing key derived from its event name and
+// waits for the broker to confirm it. It is mandatory, so a message that no
+// queue is bound for is reported as ErrUnroutable rather than dropped.
+func (p *Publisher) Publish(ctx context.Context, message events.Message) error {
+	select {
+	case <-p.done:
+		return ErrClosed
+	default:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ch == nil {
+		return ErrNotConnected
+	}
+
+	headers := amqp.Table{"aggregate_id": message.Key}
+	for k, v := range message.Headers {
+		headers[k] = v
+	}
+	err := p.ch.Publish(p.config.Topology.Exchange, events.RoutingKey(message.Name), true, false, amqp.Publishing{
+		ContentType:  message.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    message.ID,
+		Type:         message.Name,
+		Timestamp:    message.Timestamp,
+		Headers:      headers,
+		Body:         message.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			return ErrNotConnected
+		}
+		if !confirm.Ack {
+			return ErrNacked
+		}
+	case <-time.After(p.config.ConfirmTimeout):
+		// delivery tags can no longer be matched to publishes; start over
+		p.conn.Close()
+		return ErrConfirmTimeout
+	case <-ctx.Done():
+		p.conn.Close()
+		return ctx.Err()
+	}
+
+	// a basic.return always arrives before the matching ack
+	select {
+	case <-p.returns:
+		return ErrUnroutable
+	default:
+		return nil
+	}
+}
+
+// Ping reports ErrNotConnected while the publisher is reconnecting.
+func (p *Publisher) Ping(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil || p.conn.IsClosed() {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	p.once.Do(func() { close(p.done) })
+	p.reset()
+	return nil
+}
@@ -0,0 +1,84 @@
+package rabbitmq
+
+import (
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// Topology describes the exchanges and queues declared on every (re)connect.
+// Each queue gets a "<name>.dlq" dead-letter queue bound to DeadLetterExchange
+// with the same binding keys. A queue with a RetryDelay also gets a
+// "<name>.retry" queue that holds messages for that long before handing them
+// back; only the consumer sets it, so publishers never declare it with
+// conflicting arguments.
+type Topology struct {
+	Exchange           string
+	DeadLetterExchange string
+	Queues             []Queue
+}
+
+type Queue struct {
+	Name        string
+	BindingKeys []string
+	RetryDelay  time.Duration
+}
+
+func DefaultTopology() Topology {
+	return Topology{
+		Exchange:           "orders",
+		DeadLetterExchange: "orders.dlx",
+		Queues: []Queue{
+			{Name: "orders.events", BindingKeys: []string{"order.#"}},
+		},
+	}
+}
+
+func DeadLetterQueueName(queue string) string {
+	return queue + ".dlq"
+}
+
+func RetryQueueName(queue string) string {
+	return queue + ".retry"
+}
+
+// Declare is idempotent, so it is safe to run against a broker that already
+// has the topology.
+func (t Topology) Declare(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(t.Exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.ExchangeDeclare(t.DeadLetterExchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		return err
+	}
+	for _, q := range t.Queues {
+		dlq := DeadLetterQueueName(q.Name)
+		if _, err := ch.QueueDeclare(dlq, true, false, false, false, nil); err != nil {
+			return err
+		}
+		args := amqp.Table{"x-dead-letter-exchange": t.DeadLetterExchange}
+		if _, err := ch.QueueDeclare(q.Name, true, false, false, false, args); err != nil {
+			return err
+		}
+		if q.RetryDelay > 0 {
+			// expired messages go back to the work queue through the default exchange
+			retryArgs := amqp.Table{
+				"x-message-ttl":             q.RetryDelay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": q.Name,
+			}
+			if _, err := ch.QueueDeclare(RetryQueueName(q.Name), true, false, false, false, retryArgs); err != nil {
+				return err
+			}
+		}
+		for _, key := range q.BindingKeys {
+			if err := ch.QueueBind(q.Name, key, t.Exchange, false, nil); err != nil {
+				return err
+			}
+			if err := ch.QueueBind(dlq, key, t.DeadLetterExchange, false, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
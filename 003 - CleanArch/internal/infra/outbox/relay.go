@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+const (
+	DefaultBatchSize    = 100
+	DefaultPollInterval = time.Second
+	DefaultMinBackoff   = time.Second
+	DefaultMaxBackoff   = 5 * time.Minute
+	DefaultMaxAttempts  = 20
+)
+
+// Relay polls the outbox and publishes pending messages. A message is marked
+// published only after Publish succeeds, so a crash in between publishes it
+// again: delivery is at-least-once and consumers dedupe on the message ID.
+// A message that fails MaxAttempts times is marked dead and left for an
+// operator; zero means it is retried forever.
+type Relay struct {
+	Repository   entity.OutboxRepositoryInterface
+	Publisher    events.Publisher
+	BatchSize    int
+	PollInterval time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	MaxAttempts  int
+}
+
+func NewRelay(repository entity.OutboxRepositoryInterface, publisher events.Publisher) *Relay {
+	return &Relay{
+		Repository:   repository,
+		Publisher:    publisher,
+		BatchSize:    DefaultBatchSize,
+		PollInterval: DefaultPollInterval,
+		MinBackoff:   DefaultMinBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		MaxAttempts:  DefaultMaxAttempts,
+	}
+}
+
+// Run relays messages until ctx is cancelled. A full batch is followed by the
+// next one straight away; otherwise the relay waits PollInterval.
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		n, err := r.RelayBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		if err == nil && n == r.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.PollInterval):
+		}
+	}
+}
+
+// RelayBatch publishes one batch of due messages and returns how many were
+// fetched. Publish failures are recorded on the message and retried later
+// with exponential backoff; only repository errors are returned.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	messages, err := r.Repository.FetchPending(ctx, time.Now().UTC(), r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, message := range messages {
+		if err := ctx.Err(); err != nil {
+			return len(messages), err
+		}
+		if err := r.Publisher.Publish(ctx, toEventMessage(message)); err != nil {
+			if err := r.fail(ctx, message, err); err != nil {
+				return len(messages), err
+			}
+			continue
+		}
+		if err := r.Repository.MarkPublished(ctx, message.ID, time.Now().UTC()); err != nil {
+			return len(messages), err
+		}
+	}
+	return len(messages), nil
+}
+
+// fail schedules another attempt at message, or marks it dead once it has
+// used up MaxAttempts.
+func (r *Relay) fail(ctx context.Context, message entity.OutboxMessage, publishErr error) error {
+	attempts := message.Attempts + 1
+	if r.MaxAttempts > 0 && attempts >= r.MaxAttempts {
+		log.Printf("outbox relay: %s %s is dead after %d attempts: %v", message.EventName, message.ID, attempts, publishErr)
+		return r.Repository.MarkDead(ctx, message.ID, publishErr.Error(), time.Now().UTC())
+	}
+	next := time.Now().UTC().Add(r.backoff(attempts))
+	return r.Repository.MarkFailed(ctx, message.ID, publishErr.Error(), next)
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.MinBackoff
+	for i := 1; i < attempts && delay < r.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.MaxBackoff {
+		delay = r.MaxBackoff
+	}
+	return delay
+}
+
+// toEventMessage carries the trace context stored in the CloudEvent over to
+// the message headers, so publishing continues the trace of the request
+// that wrote the outbox entry.
+func toEventMessage(message entity.OutboxMessage) events.Message {
+	var headers map[string]string
+	if ce, err := events.ParseCloudEvent(message.Payload); err == nil && ce.TraceParent != "" {
+		headers = map[string]string{"traceparent": ce.TraceParent}
+		if ce.TraceState != "" {
+			headers["tracestate"] = ce.TraceState
+		}
+	}
+	return events.Message{
+		ID:          message.ID,
+		Name:        message.EventName,
+		Key:         message.AggregateID,
+		ContentType: events.CloudEventsContentType,
+		Payload:     message.Payload,
+		Timestamp:   message.CreatedAt,
+		Headers:     headers,
+	}
+}
@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+	"github.com/stretchr/testify/suite"
+)
+
+type memoryOutbox struct {
+	messages map[string]*entity.OutboxMessage
+	order    []string
+}
+
+func newMemoryOutbox(messages ...entity.OutboxMessage) *memoryOutbox {
+	m := &memoryOutbox{messages: make(map[string]*entity.OutboxMessage)}
+	for i := range messages {
+		m.messages[messages[i].ID] = &messages[i]
+		m.order = append(m.order, messages[i].ID)
+	}
+	return m
+}
+
+func (m *memoryOutbox) FetchPending(_ context.Context, now time.Time, limit int) ([]entity.OutboxMessage, error) {
+	var pending []entity.OutboxMessage
+	for _, id := range m.order {
+		msg := m.messages[id]
+		if msg.PublishedAt == nil && msg.DeadAt == nil && !msg.NextAttemptAt.After(now) && len(pending) < limit {
+			pending = append(pending, *msg)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryOutbox) MarkPublished(_ context.Context, id string, at time.Time) error {
+	m.messages[id].Attempts++
+	m.messages[id].PublishedAt = &at
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(_ context.Context, id string, reason string, nextAttemptAt time.Time) error {
+	m.messages[id].Attempts++
+	m.messages[id].LastError = reason
+	m.messages[id].NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (m *memoryOutbox) MarkDead(_ context.Context, id string, reason string, at time.Time) error {
+	m.messages[id].Attempts++
+	m.messages[id].LastError = reason
+	m.messages[id].DeadAt = &at
+	return nil
+}
+
+func (m *memoryOutbox) Stats(_ context.Context) (entity.OutboxStats, error) {
+	return entity.OutboxStats{}, nil
+}
+
+// flakyPublisher fails the first failures publishes, then delegates to an
+// in-memory publisher.
+type flakyPublisher struct {
+	*events.InMemoryPublisher
+	failures int
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, message events.Message) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	return p.InMemoryPublisher.Publish(ctx, message)
+}
+
+func messageIDs(messages []events.Message) []string {
+	var ids []string
+	for _, m := range messages {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+type RelayTestSuite struct {
+	suite.Suite
+}
+
+func TestRelaySuite(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
+
+func (suite *RelayTestSuite) TestGivenPendingMessages_WhenRelayBatch_ThenShouldPublishInOrder() {
+	repo := newMemoryOutbox(
+		entity.NewOutboxMessage("1", "a", "OrderCreated", []byte(`{}`)),
+		entity.NewOutboxMessage("2", "b", "OrderCreated", []byte(`{}`)),
+	)
+	publisher := events.NewInMemoryPublisher()
+	relay := NewRelay(repo, publisher)
+
+	n, err := relay.RelayBatch(context.Background())
+	suite.NoError(err)
+	suite.Equal(2, n)
+	suite.Equal([]string{"1", "2"}, messageIDs(publisher.Messages()))
+	suite.Equal("OrderCreated", publisher.Messages()[0].Name)
+	suite.Equal("a", publisher.Messages()[0].Key)
+	suite.NotNil(repo.messages["1"].PublishedAt)
+	suite.NotNil(repo.messages["2"].PublishedAt)
+
+	n, err = relay.RelayBatch(context.Background())
+	suite.NoError(err)
+	suite.Equal(0, n)
+}
+
+func (suite *RelayTestSuite) TestGivenABrokerFailure_WhenRelayBatch_ThenShouldRetryAfterBackoff() {
+	repo := newMemoryOutbox(entity.NewOutboxMessage("1", "a", "OrderCreated", []byte(`{}`)))
+	publisher := &flakyPublisher{InMemoryPublisher: events.NewInMemoryPublisher(), failures: 1}
+	relay := NewRelay(repo, publisher)
+
+	_, err := relay.RelayBatch(context.Background())
+	suite.NoError(err)
+	suite.Empty(publisher.Messages())
+	suite.Nil(repo.messages["1"].PublishedAt)
+	suite.Equal(1, repo.messages["1"].Attempts)
+	suite.Equal("broker unavailable", repo.messages["1"].LastError)
+	suite.True(repo.messages["1"].NextAttemptAt.After(time.Now().UTC()))
+
+	repo.messages["1"].NextAttemptAt = time.Now().UTC()
+	_, err = relay.RelayBatch(context.Background())
+	suite.NoError(err)
+	suite.Equal([]string{"1"}, messageIDs(publisher.Messages()))
+	suite.NotNil(repo.messages["1"].PublishedAt)
+}
+
+func (suite *RelayTestSuite) TestGivenAMessageThatKeepsFailing_WhenItReachesMaxAttempts_ThenShouldMarkItDead() {
+	repo := newMemoryOutbox(entity.NewOutboxMessage("1", "a", "OrderCreated", []byte(`{}`)))
+	publisher := &flakyPublisher{InMemoryPublisher: events.NewInMemoryPublisher(), failures: 3}
+	relay := NewRelay(repo, publisher)
+	relay.MaxAttempts = 3
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		repo.messages["1"].NextAttemptAt = time.Now().UTC()
+		n, err := relay.RelayBatch(context.Background())
+		suite.NoError(err)
+		suite.Equal(1, n)
+	}
+	suite.Equal(3, repo.messages["1"].Attempts)
+	suite.NotNil(repo.messages["1"].DeadAt)
+	suite.Equal("broker unavailable", repo.messages["1"].LastError)
+
+	repo.messages["1"].NextAttemptAt = time.Now().UTC()
+	n, err := relay.RelayBatch(context.Background())
+	suite.NoError(err)
+	suite.Equal(0, n)
+	suite.Empty(publisher.Messages())
+}
+
+func (suite *RelayTestSuite) TestGivenRepeatedFailures_WhenBackoff_ThenShouldDoubleUpToMax() {
+	relay := NewRelay(newMemoryOutbox(), events.NewInMemoryPublisher())
+	relay.MinBackoff = time.Second
+	relay.MaxBackoff = 10 * time.Second
+
+	suite.Equal(time.Second, relay.backoff(1))
+	suite.Equal(2*time.Second, relay.backoff(2))
+	suite.Equal(8*time.Second, relay.backoff(4))
+	suite.Equal(10*time.Second, relay.backoff(5))
+	suite.Equal(10*time.Second, relay.backoff(50))
+}
+
+func (suite *RelayTestSuite) TestGivenACloudEventWithTraceContext_WhenRelayBatch_ThenShouldPublishItAsHeaders() {
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	payload := []byte(`{"specversion":"1.0","id":"1","source":"/test","type":"OrderCreated","time":"2024-01-01T00:00:00Z","traceparent":"` + traceParent + `","data":{}}`)
+	repo := newMemoryOutbox(entity.NewOutboxMessage("1", "a", "OrderCreated", payload))
+	publisher := events.NewInMemoryPublisher()
+
+	_, err := NewRelay(repo, publisher).RelayBatch(context.Background())
+	suite.NoError(err)
+	suite.Require().Len(publisher.Messages(), 1)
+	suite.Equal(map[string]string{"traceparent": traceParent}, publisher.Messages()[0].Headers)
+}
@@ -0,0 +1,167 @@
+// Package server runs the HTTP, gRPC and GraphQL servers as one group that
+// starts together and shuts down together.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Group runs servers until its context ends or one of them fails, then
+// shuts all of them down: first the OnShutdown hooks, such as marking the
+// process not ready, then DrainDelay so load balancers notice, then every
+// server's shutdown at once within ShutdownTimeout.
+type Group struct {
+	ShutdownTimeout time.Duration
+	DrainDelay      time.Duration
+
+	servers []member
+	hooks   []func()
+}
+
+type member struct {
+	name     string
+	serve    func() error
+	shutdown func(ctx context.Context) error
+}
+
+func NewGroup() *Group {
+	return &Group{ShutdownTimeout: 30 * time.Second}
+}
+
+// Add registers a server. serve blocks until the server stops; shutdown
+// stops it gracefully and must return once ctx ends.
+func (g *Group) Add(name string, serve func() error, shutdown func(ctx context.Context) error) {
+	g.servers = append(g.servers, member{name: name, serve: serve, shutdown: shutdown})
+}
+
+// AddHTTP serves srv on lis and shuts it down with http.Server.Shutdown.
+func (g *Group) AddHTTP(name string, srv *http.Server, lis net.Listener) {
+	g.Add(name, func() error {
+		if err := srv.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}, srv.Shutdown)
+}
+
+// AddGRPC serves srv on lis. Shutdown waits for running calls and falls
+// back to closing them when the timeout passes.
+func (g *Group) AddGRPC(name string, srv *grpc.Server, lis net.Listener) {
+	g.Add(name, func() error {
+		if err := srv.Serve(lis); !errors.Is(err, grpc.ErrServerStopped) {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context) error {
+		return stopGRPC(ctx, srv)
+	})
+}
+
+// AddMux runs m, which must be added along with the servers of its
+// listeners. Shutdown closes the shared listener.
+func (g *Group) AddMux(name string, m *Mux) {
+	g.Add(name, m.Serve, func(context.Context) error {
+		return m.Close()
+	})
+}
+
+func stopGRPC(ctx context.Context, srv *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// OnShutdown registers fn to run, in order, before the servers shut down.
+func (g *Group) OnShutdown(fn func()) {
+	g.hooks = append(g.hooks, fn)
+}
+
+// Run starts every server and blocks until all of them stopped. It returns
+// the errors of servers that failed and of shutdowns that did not finish in
+// time.
+func (g *Group) Run(ctx context.Context) error {
+	if len(g.servers) == 0 {
+		return nil
+	}
+	stopping := make(chan struct{})
+	errs := make(chan error, len(g.servers))
+	for _, m := range g.servers {
+		go func(m member) {
+			err := m.serve()
+			select {
+			case <-stopping:
+			default:
+				if err == nil {
+					err = errors.New("stopped unexpectedly")
+				}
+			}
+			if err != nil {
+				err = fmt.Errorf("%s: %w", m.name, err)
+			}
+			errs <- err
+		}(m)
+	}
+
+	var result []error
+	running := len(g.servers)
+	select {
+	case <-ctx.Done():
+	case err := <-errs:
+		running--
+		result = append(result, err)
+	}
+	close(stopping)
+	result = append(result, g.shutdown())
+	for ; running > 0; running-- {
+		result = append(result, <-errs)
+	}
+	return errors.Join(result...)
+}
+
+func (g *Group) shutdown() error {
+	for _, hook := range g.hooks {
+		hook()
+	}
+	if g.DrainDelay > 0 {
+		log.Printf("server: draining for %s", g.DrainDelay)
+		time.Sleep(g.DrainDelay)
+	}
+
+	ctx := context.Background()
+	if g.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.ShutdownTimeout)
+		defer cancel()
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(g.servers))
+	for i, m := range g.servers {
+		wg.Add(1)
+		go func(i int, m member) {
+			defer wg.Done()
+			if err := m.shutdown(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s shutdown: %w", m.name, err)
+			}
+		}(i, m)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
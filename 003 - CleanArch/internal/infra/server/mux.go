@@ -0,0 +1,154 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"sync"
+	"time"
+)
+
+// http2Preface opens every HTTP/2 connection made without TLS. Browsers
+// never speak cleartext HTTP/2, so on this port it means gRPC.
+var http2Preface = []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")
+
+// Mux shares one listener between the gRPC server and the HTTP server, in
+// the manner of cmux: it reads the start of each connection and hands it to
+// GRPC when it opens with the HTTP/2 preface, otherwise to HTTP. Each
+// server then runs unchanged on its own listener, graceful stop included.
+type Mux struct {
+	// SniffTimeout bounds how long a new connection may take to send its
+	// first bytes.
+	SniffTimeout time.Duration
+
+	root net.Listener
+	grpc *muxListener
+	http *muxListener
+	once sync.Once
+	done chan struct{}
+}
+
+func NewMux(lis net.Listener) *Mux {
+	return &Mux{
+		SniffTimeout: 10 * time.Second,
+		root:         lis,
+		grpc:         newMuxListener(lis.Addr()),
+		http:         newMuxListener(lis.Addr()),
+		done:         make(chan struct{}),
+	}
+}
+
+// GRPC returns the listener for HTTP/2 connections.
+func (m *Mux) GRPC() net.Listener { return m.grpc }
+
+// HTTP returns the listener for every other connection.
+func (m *Mux) HTTP() net.Listener { return m.http }
+
+// Serve accepts connections until Close.
+func (m *Mux) Serve() error {
+	for {
+		conn, err := m.root.Accept()
+		if err != nil {
+			select {
+			case <-m.done:
+				return nil
+			default:
+				return err
+			}
+		}
+		go m.route(conn)
+	}
+}
+
+// Close stops accepting; connections already handed out are left to their
+// servers.
+func (m *Mux) Close() error {
+	var err error
+	m.once.Do(func() {
+		close(m.done)
+		err = m.root.Close()
+	})
+	return err
+}
+
+func (m *Mux) route(conn net.Conn) {
+	if m.SniffTimeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(m.SniffTimeout))
+	}
+	reader := bufio.NewReaderSize(conn, len(http2Preface))
+	isHTTP2, err := hasPreface(reader)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	target := m.http
+	if isHTTP2 {
+		target = m.grpc
+	}
+	target.deliver(&sniffedConn{Conn: conn, reader: reader})
+}
+
+// hasPreface peeks one byte more at a time, so an HTTP/1 request is told
+// apart as soon as it differs rather than after a full preface worth of
+// bytes.
+func hasPreface(reader *bufio.Reader) (bool, error) {
+	for n := 1; n <= len(http2Preface); n++ {
+		peeked, err := reader.Peek(n)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(peeked, http2Preface[:n]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// sniffedConn replays the bytes read while routing.
+type sniffedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *sniffedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+type muxListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+	once  sync.Once
+	done  chan struct{}
+}
+
+func newMuxListener(addr net.Addr) *muxListener {
+	return &muxListener{addr: addr, conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *muxListener) deliver(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		conn.Close()
+	}
+}
+
+func (l *muxListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close only stops this listener, as servers close theirs when they shut
+// down; the shared one is closed by Mux.Close.
+func (l *muxListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *muxListener) Addr() net.Addr { return l.addr }
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return lis
+}
+
+func runGroup(group *Group) (context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- group.Run(ctx) }()
+	return cancel, done
+}
+
+func wait(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("group did not stop")
+		return nil
+	}
+}
+
+func TestGivenOnePortForGRPCAndHTTP_WhenCalled_ThenShouldRouteEachProtocol(t *testing.T) {
+	lis := listen(t)
+	mux := NewMux(lis)
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, grpchealth.NewServer())
+	httpServer := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	})}
+
+	group := NewGroup()
+	group.AddMux("mux", mux)
+	group.AddGRPC("grpc", grpcServer, mux.GRPC())
+	group.AddHTTP("http", httpServer, mux.HTTP())
+	cancel, done := runGroup(group)
+
+	resp, err := http.Get("http://" + lis.Addr().String() + "/")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "hello", string(body))
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	check, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check.Status)
+
+	cancel()
+	assert.NoError(t, wait(t, done))
+}
+
+func TestGivenARunningGroup_WhenCanceled_ThenShouldRunTheHooksBeforeShuttingDown(t *testing.T) {
+	var events []string
+	group := NewGroup()
+	stopped := make(chan struct{})
+	group.Add("server", func() error {
+		<-stopped
+		return nil
+	}, func(context.Context) error {
+		events = append(events, "shutdown")
+		close(stopped)
+		return nil
+	})
+	group.OnShutdown(func() { events = append(events, "hook") })
+	cancel, done := runGroup(group)
+
+	cancel()
+
+	assert.NoError(t, wait(t, done))
+	assert.Equal(t, []string{"hook", "shutdown"}, events)
+}
+
+func TestGivenAServerThatFails_WhenRun_ThenShouldStopTheOthersAndReturnItsError(t *testing.T) {
+	httpServer := &http.Server{Handler: http.NotFoundHandler()}
+	group := NewGroup()
+	group.AddHTTP("web", httpServer, listen(t))
+	group.Add("broken", func() error {
+		return errors.New("address in use")
+	}, func(context.Context) error { return nil })
+	_, done := runGroup(group)
+
+	err := wait(t, done)
+
+	assert.EqualError(t, err, "broken: address in use")
+}
+
+func TestGivenAShutdownThatHangs_WhenTheTimeoutPasses_ThenShouldReportIt(t *testing.T) {
+	group := NewGroup()
+	group.ShutdownTimeout = 20 * time.Millisecond
+	stopped := make(chan struct{})
+	group.Add("slow", func() error {
+		<-stopped
+		return nil
+	}, func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return ctx.Err()
+	})
+	cancel, done := runGroup(group)
+
+	cancel()
+
+	assert.ErrorIs(t, wait(t, done), context.DeadlineExceeded)
+}
@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	"database/sql"
+
+	"CleanArch/internal/infra/database/dialect"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// OpenDB opens d's database with a span per query and statement and
+// registers the connection pool statistics as metrics. Query spans carry the
+// SQL text, which holds ? placeholders and never the argument values.
+func OpenDB(d dialect.Dialect, dsn string) (*sql.DB, error) {
+	options := []otelsql.Option{
+		otelsql.WithAttributes(dbSystem(d)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	}
+	db, err := otelsql.Open(d.DriverName(), dsn, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := otelsql.RegisterDBStatsMetrics(db, options...); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func dbSystem(d dialect.Dialect) attribute.KeyValue {
+	switch d {
+	case dialect.Postgres:
+		return semconv.DBSystemPostgreSQL
+	case dialect.SQLite:
+		return semconv.DBSystemSqlite
+	default:
+		return semconv.DBSystemMySQL
+	}
+}
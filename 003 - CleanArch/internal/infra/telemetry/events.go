@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/pkg/events"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// TraceEventHandlers is an events.Middleware that runs every in-process
+// handler in its own span.
+func TraceEventHandlers() events.Middleware {
+	tracer := otel.Tracer(instrumentationName)
+	return func(next events.EventHandlerInterface) events.EventHandlerInterface {
+		return events.HandlerFunc(func(ctx context.Context, event events.EventInterface) error {
+			ctx, span := tracer.Start(ctx, event.GetName()+" handle")
+			defer span.End()
+			err := next.Handle(ctx, event)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		})
+	}
+}
+
+// EventHandlerMetrics records events.Metrics observations as a histogram.
+type EventHandlerMetrics struct {
+	duration metric.Float64Histogram
+}
+
+func NewEventHandlerMetrics() *EventHandlerMetrics {
+	duration, _ := otel.Meter(instrumentationName).Float64Histogram("events.handler.duration",
+		metric.WithDescription("In-process event handler time by event and outcome."),
+		metric.WithUnit("s"))
+	return &EventHandlerMetrics{duration: duration}
+}
+
+func (m *EventHandlerMetrics) ObserveHandler(eventName string, duration time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	m.duration.Record(context.Background(), duration.Seconds(), metric.WithAttributes(
+		attribute.String("event", eventName),
+		attribute.String("outcome", outcome),
+	))
+}
@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/pkg/events"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Publisher wraps an events.Publisher with a producer span per message. The
+// span continues the trace already carried in the message headers, such as
+// the one the outbox relay copies from the CloudEvent, and its own context
+// replaces it there. Every broker adapter copies the headers into the
+// native ones: AMQP table, Kafka record headers or NATS headers.
+type Publisher struct {
+	Next   events.Publisher
+	System string
+
+	duration metric.Float64Histogram
+}
+
+// NewPublisher wraps next; system names the broker, like "rabbitmq".
+func NewPublisher(next events.Publisher, system string) *Publisher {
+	duration, _ := otel.Meter(instrumentationName).Float64Histogram("messaging.publish.duration",
+		metric.WithDescription("Time to publish a message and have the broker accept it."),
+		metric.WithUnit("s"))
+	return &Publisher{Next: next, System: system, duration: duration}
+}
+
+func (p *Publisher) Publish(ctx context.Context, message events.Message) error {
+	propagator := otel.GetTextMapPropagator()
+	headers := make(map[string]string, len(message.Headers)+2)
+	for k, v := range message.Headers {
+		headers[k] = v
+	}
+	ctx = propagator.Extract(ctx, propagation.MapCarrier(headers))
+
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, message.Name+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String(p.System),
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(events.RoutingKey(message.Name)),
+			semconv.MessagingMessageID(message.ID),
+		))
+	defer span.End()
+	propagator.Inject(ctx, propagation.MapCarrier(headers))
+	message.Headers = headers
+
+	start := time.Now()
+	err := p.Next.Publish(ctx, message)
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	p.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		semconv.MessagingSystemKey.String(p.System),
+		attribute.String("event", message.Name),
+		attribute.String("outcome", outcome),
+	))
+	return err
+}
+
+func (p *Publisher) Close() error {
+	return p.Next.Close()
+}
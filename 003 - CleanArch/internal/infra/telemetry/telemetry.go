@@ -0,0 +1,125 @@
+// Package telemetry sets up OpenTelemetry tracing and metrics and adapts the
+// service's ports, such as events.Publisher, to them. The code that produces
+// spans and measurements only uses the global otel API, so nothing is
+// recorded until Setup installs an exporter.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+const instrumentationName = "CleanArch/internal/infra/telemetry"
+
+var ErrUnknownExporter = errors.New("unknown telemetry exporter")
+
+type Config struct {
+	// Exporter is ExporterNone, ExporterStdout or ExporterOTLP.
+	Exporter    string
+	ServiceName string
+	// OTLPEndpoint is the host:port of an OTLP/gRPC collector.
+	OTLPEndpoint string
+	OTLPInsecure bool
+	// SampleRatio is the fraction of new traces to sample; traces started
+	// upstream follow the caller's decision.
+	SampleRatio    float64
+	MetricInterval time.Duration
+}
+
+// Setup installs the W3C trace context propagator and, unless the exporter
+// is ExporterNone, global tracer and meter providers. The returned function
+// flushes and stops them.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var spanExporter sdktrace.SpanExporter
+	var metricExporter sdkmetric.Exporter
+	var err error
+	switch config.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New()
+		if err == nil {
+			metricExporter, err = stdoutmetric.New()
+		}
+	case ExporterOTLP:
+		spanExporter, metricExporter, err = newOTLPExporters(ctx, config)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(semconv.ServiceName(config.ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	var readerOptions []sdkmetric.PeriodicReaderOption
+	if config.MetricInterval > 0 {
+		readerOptions = append(readerOptions, sdkmetric.WithInterval(config.MetricInterval))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, readerOptions...)),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+func newOTLPExporters(ctx context.Context, config Config) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	traceOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.OTLPEndpoint)}
+	metricOptions := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(config.OTLPEndpoint)}
+	if config.OTLPInsecure {
+		traceOptions = append(traceOptions, otlptracegrpc.WithInsecure())
+		metricOptions = append(metricOptions, otlpmetricgrpc.WithInsecure())
+	}
+	spanExporter, err := otlptracegrpc.New(ctx, traceOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	metricExporter, err := otlpmetricgrpc.New(ctx, metricOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return spanExporter, metricExporter, nil
+}
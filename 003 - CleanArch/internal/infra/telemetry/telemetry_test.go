@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"CleanArch/internal/event"
+	"CleanArch/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const incomingTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// recordSpans installs a tracer provider that keeps ended spans in memory.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, events.Message) error {
+	return errors.New("broker down")
+}
+func (failingPublisher) Close() error { return nil }
+
+func TestGivenAMessageWithTraceContext_WhenPublish_ThenShouldInjectTheProducerSpan(t *testing.T) {
+	recorder := recordSpans(t)
+	broker := events.NewInMemoryPublisher()
+	publisher := NewPublisher(broker, "rabbitmq")
+
+	err := publisher.Publish(context.Background(), events.Message{
+		ID:      "1",
+		Name:    "OrderCreated",
+		Headers: map[string]string{"traceparent": incomingTraceParent, "aggregate_id": "a"},
+	})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "OrderCreated publish", span.Name())
+	assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+
+	headers := broker.Messages()[0].Headers
+	assert.Equal(t, "a", headers["aggregate_id"])
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.SpanContext().SpanID().String()+"-01", headers["traceparent"])
+}
+
+func TestGivenABrokerFailure_WhenPublish_ThenShouldMarkTheSpanAsFailed(t *testing.T) {
+	recorder := recordSpans(t)
+
+	err := NewPublisher(failingPublisher{}, "kafka").Publish(context.Background(), events.Message{ID: "1", Name: "OrderPaid"})
+	assert.EqualError(t, err, "broker down")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestGivenAnEventHandler_WhenTraced_ThenShouldRunInAChildSpan(t *testing.T) {
+	recorder := recordSpans(t)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+	var handlerSpan trace.SpanContext
+	handler := TraceEventHandlers()(events.HandlerFunc(func(ctx context.Context, _ events.EventInterface) error {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil
+	}))
+	require.NoError(t, handler.Handle(ctx, event.NewOrderCreated()))
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "OrderCreated handle", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), handlerSpan.SpanID())
+}
+
+func TestGivenAnUnknownExporter_WhenSetup_ThenShouldFail(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.ErrorIs(t, err, ErrUnknownExporter)
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
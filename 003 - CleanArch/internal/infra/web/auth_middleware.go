@@ -0,0 +1,45 @@
+package web
+
+import (
+	"net/http"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/infra/auth"
+)
+
+// Authenticate stores the principal of a valid bearer token in the request
+// context. Requests with an invalid token get 401; requests without one pass
+// through unauthenticated, for a later check to reject.
+func Authenticate(verifier *auth.Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			principal, err := verifier.VerifyAuthorization(header)
+			if err != nil {
+				apierror.WriteHTTP(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// RequirePermission requires orders:read for safe methods and orders:write
+// for the others.
+func RequirePermission(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		permission := auth.PermissionWrite
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			permission = auth.PermissionRead
+		}
+		if err := auth.Authorize(r.Context(), permission); err != nil {
+			apierror.WriteHTTP(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,94 @@
+// Package openapi holds the OpenAPI 3 document of the REST API and checks
+// requests against it.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Spec is the OpenAPI document served at /openapi.json.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// Document is the part of an OpenAPI 3.0 document that request validation
+// and the contract tests read.
+type Document struct {
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type Operation struct {
+	OperationID string       `json:"operationId"`
+	Parameters  []*Parameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema *Schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// Schema supports the JSON Schema keywords the document uses;
+// additionalProperties may only be a boolean.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []string           `json:"enum"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MaxLength            *int               `json:"maxLength"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+}
+
+// Load parses Spec.
+func Load() (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	return &doc, nil
+}
+
+// Operation returns the operation for method on path, a chi pattern such as
+// "/v1/orders/{id}", which matches OpenAPI path templates.
+func (d *Document) Operation(method, path string) (*Operation, bool) {
+	op, ok := d.Paths[path][strings.ToLower(method)]
+	return op, ok && op != nil
+}
+
+// Resolve follows s's $ref, if any, to a schema under components.
+func (d *Document) Resolve(s *Schema) (*Schema, error) {
+	for s != nil && s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok || d.Components.Schemas[name] == nil {
+			return nil, fmt.Errorf("openapi: unresolved $ref %q", s.Ref)
+		}
+		s = d.Components.Schemas[name]
+	}
+	return s, nil
+}
+
+// BodySchema is the JSON request body schema of op, or nil without one.
+func (op *Operation) BodySchema() *Schema {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return op.RequestBody.Content["application/json"].Schema
+}
@@ -0,0 +1,239 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/apierror"
+)
+
+var ErrUnknownField = errors.New("is not a known field")
+
+// Validate returns a middleware that checks requests against the operation
+// for method and path before the handler runs. Mistyped query parameters and
+// JSON bodies that break the schema get 422 listing every rejected field; a
+// body that is not JSON gets 400. Undeclared query parameters are ignored.
+func (d *Document) Validate(method, path string) (func(http.Handler) http.Handler, error) {
+	op, ok := d.Operation(method, path)
+	if !ok {
+		return nil, fmt.Errorf("openapi: no operation for %s %s", method, path)
+	}
+	body, err := d.Resolve(op.BodySchema())
+	if err != nil {
+		return nil, err
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var errs entity.ValidationError
+			d.validateQuery(op, r, &errs)
+			if body != nil {
+				value, err := readJSON(r)
+				if err != nil {
+					apierror.WriteHTTP(w, r, apierror.Malformed(err))
+					return
+				}
+				if value == nil && op.RequestBody.Required {
+					apierror.WriteHTTP(w, r, apierror.Malformed(errors.New("request body is required")))
+					return
+				}
+				if value != nil {
+					d.ValidateValue(body, value, "", &errs)
+				}
+			}
+			if err := errs.Err(); err != nil {
+				apierror.WriteHTTP(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// readJSON decodes the body, keeping numbers as json.Number, and puts it
+// back for the handler. An empty body yields nil.
+func readJSON(r *http.Request) (any, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON body")
+	}
+	return value, nil
+}
+
+func (d *Document) validateQuery(op *Operation, r *http.Request, errs *entity.ValidationError) {
+	query := r.URL.Query()
+	for _, parameter := range op.Parameters {
+		if parameter.In != "query" {
+			continue
+		}
+		raw, present := query[parameter.Name]
+		if !present || raw[0] == "" {
+			if parameter.Required {
+				errs.Add(parameter.Name, entity.ErrRequired)
+			}
+			continue
+		}
+		schema, err := d.Resolve(parameter.Schema)
+		if err != nil {
+			errs.Add(parameter.Name, err)
+			continue
+		}
+		d.ValidateValue(schema, queryValue(schema, raw[0]), parameter.Name, errs)
+	}
+}
+
+// queryValue converts a query string to the JSON value schema expects, so
+// one validator serves both; a value that does not convert stays a string
+// and fails the type check.
+func queryValue(schema *Schema, raw string) any {
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+// ValidateValue checks value, decoded with json.Decoder.UseNumber, against
+// s and adds each violation to errs under its JSON path, such as
+// "items[0].quantity".
+func (d *Document) ValidateValue(s *Schema, value any, path string, errs *entity.ValidationError) {
+	s, err := d.Resolve(s)
+	if err != nil {
+		errs.Add(fieldName(path), err)
+		return
+	}
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			errs.Add(fieldName(path), errors.New("must be an object"))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				errs.Add(childPath(path, name), entity.ErrRequired)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					errs.Add(childPath(path, name), ErrUnknownField)
+				}
+				continue
+			}
+			d.ValidateValue(property, object[name], childPath(path, name), errs)
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			errs.Add(fieldName(path), errors.New("must be an array"))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range array {
+				d.ValidateValue(s.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			errs.Add(fieldName(path), errors.New("must be a string"))
+			return
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			errs.Add(fieldName(path), fmt.Errorf("must be one of %s", strings.Join(s.Enum, ", ")))
+		}
+		if s.MaxLength != nil && len([]rune(str)) > *s.MaxLength {
+			errs.Add(fieldName(path), entity.ErrTooLong)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				errs.Add(fieldName(path), errors.New("must be an RFC 3339 date-time"))
+			}
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			errs.Add(fieldName(path), fmt.Errorf("must be a %s", numberKind(s.Type)))
+			return
+		}
+		n, err := number.Float64()
+		if err == nil && s.Type == "integer" {
+			_, err = number.Int64()
+		}
+		if err != nil {
+			errs.Add(fieldName(path), fmt.Errorf("must be a %s", numberKind(s.Type)))
+			return
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			errs.Add(fieldName(path), fmt.Errorf("must be at least %v", *s.Minimum))
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			errs.Add(fieldName(path), fmt.Errorf("must be at most %v", *s.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs.Add(fieldName(path), errors.New("must be a boolean"))
+		}
+	}
+}
+
+func numberKind(schemaType string) string {
+	if schemaType == "integer" {
+		return "whole number"
+	}
+	return "number"
+}
+
+func childPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// fieldName names the body itself "body".
+func fieldName(path string) string {
+	if path == "" {
+		return "body"
+	}
+	return path
+}
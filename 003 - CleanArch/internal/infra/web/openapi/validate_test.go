@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"CleanArch/internal/infra/apierror"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serve runs request through the validation of method and path, returning
+// the response and the body the handler received, if it ran.
+func serve(t *testing.T, method, path string, request *http.Request) (*httptest.ResponseRecorder, string) {
+	doc, err := Load()
+	require.NoError(t, err)
+	validate, err := doc.Validate(method, path)
+	require.NoError(t, err)
+
+	var received string
+	handler := validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response, received
+}
+
+func problemFields(t *testing.T, response *httptest.ResponseRecorder) map[string]string {
+	var problem apierror.ProblemDetails
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&problem))
+	fields := make(map[string]string)
+	for _, violation := range problem.Errors {
+		fields[violation.Field] = violation.Message
+	}
+	return fields
+}
+
+func TestGivenAValidBody_WhenValidated_ThenShouldReachTheHandlerUnchanged(t *testing.T) {
+	body := `{"currency": "BRL", "items": [{"sku": "SKU-1", "quantity": 2, "unit_price": 1000}]}`
+	response, received := serve(t, http.MethodPost, "/v1/orders", httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, body, received)
+}
+
+func TestGivenABodyBreakingTheSchema_WhenValidated_ThenShouldListEveryRejectedField(t *testing.T) {
+	body := `{"currency": 10, "coupon": "X", "items": [{"sku": "SKU-1", "quantity": 1.5, "unit_price": "10"}]}`
+	response, received := serve(t, http.MethodPost, "/v1/orders", httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Empty(t, received)
+	assert.Equal(t, map[string]string{
+		"currency":            "must be a string",
+		"coupon":              "is not a known field",
+		"items[0].quantity":   "must be a whole number",
+		"items[0].unit_price": "must be a whole number",
+	}, problemFields(t, response))
+}
+
+func TestGivenABodyThatIsNotJSON_WhenValidated_ThenShouldRejectItAsMalformed(t *testing.T) {
+	response, _ := serve(t, http.MethodPut, "/v1/orders/{id}", httptest.NewRequest(http.MethodPut, "/v1/orders/a", strings.NewReader(`{"currency":`)))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response, _ = serve(t, http.MethodPut, "/v1/orders/{id}", httptest.NewRequest(http.MethodPut, "/v1/orders/a", nil))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestGivenInvalidQueryParameters_WhenValidated_ThenShouldListThem(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/v1/orders?page_size=500&status=lost&created_from=yesterday&min_price=abc&unknown=1", nil)
+	response, _ := serve(t, http.MethodGet, "/v1/orders", request)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Equal(t, map[string]string{
+		"page_size":    "must be at most 100",
+		"status":       "must be one of pending, paid, shipped, delivered, cancelled, refunded",
+		"created_from": "must be an RFC 3339 date-time",
+		"min_price":    "must be a whole number",
+	}, problemFields(t, response))
+}
@@ -0,0 +1,146 @@
+package web
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/infra/web/openapi"
+	"CleanArch/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadSpec(t *testing.T) *openapi.Document {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	return doc
+}
+
+func TestGivenTheV1Routes_WhenComparedWithTheSpec_ThenShouldListTheSameOperations(t *testing.T) {
+	doc := loadSpec(t)
+
+	var routes, operations []string
+	for _, route := range (&OrderAPI{}).Routes() {
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+	for path, methods := range doc.Paths {
+		for method := range methods {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	assert.ElementsMatch(t, operations, routes)
+
+	_, err := ValidatedRoutes(doc, (&OrderAPI{}).Routes())
+	assert.NoError(t, err)
+}
+
+func TestGivenTheResponseDTOs_WhenComparedWithTheSpec_ThenShouldHaveTheSameFields(t *testing.T) {
+	doc := loadSpec(t)
+	for name, dto := range map[string]any{
+		"Order":        usecase.OrderOutputDTO{},
+		"OrderPage":    usecase.ListOrdersOutputDTO{},
+		"OutboxStatus": usecase.OutboxStatusOutputDTO{},
+		"Problem":      apierror.ProblemDetails{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assertSchemaMatches(t, doc, doc.Components.Schemas[name], reflect.TypeOf(dto), name, true)
+		})
+	}
+}
+
+func TestGivenTheRequestDTOs_WhenComparedWithTheSpec_ThenShouldDecodeEveryField(t *testing.T) {
+	doc := loadSpec(t)
+	for name, dto := range map[string]any{
+		"CreateOrderRequest":  usecase.OrderInputDTO{},
+		"ReplaceOrderRequest": usecase.UpdateOrderInputDTO{},
+		"PatchOrderRequest":   usecase.UpdateOrderInputDTO{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assertSchemaMatches(t, doc, doc.Components.Schemas[name], reflect.TypeOf(dto), name, false)
+		})
+	}
+}
+
+// assertSchemaMatches checks that schema describes how typ encodes to JSON.
+// With exact, as for responses, every JSON field must be documented and the
+// ones never omitted must be required; otherwise, as for requests, every
+// documented property must be a field the handler decodes.
+func assertSchemaMatches(t *testing.T, doc *openapi.Document, schema *openapi.Schema, typ reflect.Type, path string, exact bool) {
+	t.Helper()
+	schema, err := doc.Resolve(schema)
+	require.NoError(t, err, path)
+	require.NotNil(t, schema, path)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == reflect.TypeOf(time.Time{}):
+		assert.Equal(t, "string", schema.Type, path)
+		assert.Equal(t, "date-time", schema.Format, path)
+	case typ.Kind() == reflect.Struct:
+		require.Equal(t, "object", schema.Type, path)
+		fields, required := jsonFields(typ)
+		for name := range schema.Properties {
+			if _, ok := fields[name]; !ok {
+				t.Errorf("%s.%s is in the spec but not in %s", path, name, typ)
+			}
+		}
+		for name, field := range fields {
+			property, ok := schema.Properties[name]
+			if !ok {
+				if exact {
+					t.Errorf("%s.%s is sent by %s but not in the spec", path, name, typ)
+				}
+				continue
+			}
+			assertSchemaMatches(t, doc, property, field, path+"."+name, exact)
+		}
+		if exact {
+			assert.ElementsMatch(t, required, schema.Required, path+" required")
+		}
+	case typ.Kind() == reflect.Slice:
+		require.Equal(t, "array", schema.Type, path)
+		assertSchemaMatches(t, doc, schema.Items, typ.Elem(), path+"[]", exact)
+	case typ.Kind() == reflect.String:
+		assert.Equal(t, "string", schema.Type, path)
+	case typ.Kind() == reflect.Bool:
+		assert.Equal(t, "boolean", schema.Type, path)
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		assert.Equal(t, "number", schema.Type, path)
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		assert.Equal(t, "integer", schema.Type, path)
+	default:
+		t.Errorf("%s: unsupported type %s", path, typ)
+	}
+}
+
+// jsonFields returns the JSON names of typ's encoded fields and the ones
+// never omitted.
+func jsonFields(typ reflect.Type) (map[string]reflect.Type, []string) {
+	fields := make(map[string]reflect.Type)
+	var required []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+		if !slices.Contains(strings.Split(options, ","), "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return fields, required
+}
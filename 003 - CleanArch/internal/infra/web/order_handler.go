@@ -2,28 +2,43 @@ package web
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/infra/auth"
 	"CleanArch/internal/usecase"
 	"CleanArch/pkg/events"
+
+	"github.com/go-chi/chi/v5"
 )
 
 type WebOrderHandler struct {
-	EventDispatcher   events.EventDispatcherInterface
-	OrderRepository   entity.OrderRepositoryInterface
-	OrderCreatedEvent events.EventInterface
+	EventDispatcher       events.EventDispatcherInterface
+	OrderRepository       entity.OrderRepositoryInterface
+	IdempotencyRepository entity.IdempotencyRepositoryInterface
+	OrderCreatedEvent     events.EventFactory
+	// OperationTimeout, when set, gives the deadline of each use case by name.
+	OperationTimeout func(operation string) time.Duration
+	// IdempotencyTTL overrides usecase.DefaultIdempotencyTTL when set.
+	IdempotencyTTL time.Duration
 }
 
 func NewWebOrderHandler(
 	EventDispatcher events.EventDispatcherInterface,
 	OrderRepository entity.OrderRepositoryInterface,
-	OrderCreatedEvent events.EventInterface,
+	IdempotencyRepository entity.IdempotencyRepositoryInterface,
+	OrderCreatedEvent events.EventFactory,
 ) *WebOrderHandler {
 	return &WebOrderHandler{
-		EventDispatcher:   EventDispatcher,
-		OrderRepository:   OrderRepository,
-		OrderCreatedEvent: OrderCreatedEvent,
+		EventDispatcher:       EventDispatcher,
+		OrderRepository:       OrderRepository,
+		IdempotencyRepository: IdempotencyRepository,
+		OrderCreatedEvent:     OrderCreatedEvent,
 	}
 }
 
@@ -31,33 +46,117 @@ func (h *WebOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var dto usecase.OrderInputDTO
 	err := json.NewDecoder(r.Body).Decode(&dto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.WriteHTTP(w, r, apierror.Malformed(err))
 		return
 	}
+	dto.IdempotencyKey = r.Header.Get("Idempotency-Key")
+	dto.CreatedBy = auth.Subject(r.Context())
 
-	createOrder := usecase.NewCreateOrderUseCase(h.OrderRepository, h.OrderCreatedEvent, h.EventDispatcher)
-	output, err := createOrder.Execute(dto)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	createOrder := usecase.NewCreateOrderUseCase(h.OrderRepository, h.IdempotencyRepository, h.OrderCreatedEvent, h.EventDispatcher)
+	createOrder.Timeout = h.timeout("CreateOrder")
+	if h.IdempotencyTTL > 0 {
+		createOrder.IdempotencyTTL = h.IdempotencyTTL
 	}
-	err = json.NewEncoder(w).Encode(output)
+	output, err := createOrder.Execute(r.Context(), dto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.WriteHTTP(w, r, err)
 		return
 	}
+	writeJSON(w, output)
 }
 
+// List accepts page_size, after, status, min_price, max_price, created_from,
+// created_to (RFC 3339), sort (created_at or final_price) and order (asc or desc).
 func (h *WebOrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	input, err := parseListOrdersQuery(r.URL.Query())
+	if err != nil {
+		apierror.WriteHTTP(w, r, apierror.Malformed(err))
+		return
+	}
 	listOrders := usecase.NewListOrdersUseCase(h.OrderRepository)
-	output, err := listOrders.ListOrders()
+	listOrders.Timeout = h.timeout("ListOrders")
+	output, err := listOrders.ListOrders(r.Context(), input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.WriteHTTP(w, r, err)
 		return
 	}
-	err = json.NewEncoder(w).Encode(output)
+	writeJSON(w, output)
+}
+
+func (h *WebOrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	getOrder := usecase.NewGetOrderUseCase(h.OrderRepository)
+	getOrder.Timeout = h.timeout("GetOrder")
+	output, err := getOrder.Execute(r.Context(), usecase.GetOrderInputDTO{ID: chi.URLParam(r, "id")})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.WriteHTTP(w, r, err)
 		return
 	}
+	writeJSON(w, output)
+}
+
+func (h *WebOrderHandler) timeout(operation string) time.Duration {
+	if h.OperationTimeout == nil {
+		return 0
+	}
+	return h.OperationTimeout(operation)
+}
+
+func parseListOrdersQuery(query url.Values) (usecase.ListOrdersInputDTO, error) {
+	input := usecase.ListOrdersInputDTO{
+		After:  query.Get("after"),
+		Status: query.Get("status"),
+		SortBy: query.Get("sort"),
+	}
+	var err error
+	if v := query.Get("page_size"); v != "" {
+		if input.PageSize, err = strconv.Atoi(v); err != nil {
+			return input, fmt.Errorf("invalid page_size: %w", err)
+		}
+	}
+	if input.MinPrice, err = parseInt64Param(query, "min_price"); err != nil {
+		return input, err
+	}
+	if input.MaxPrice, err = parseInt64Param(query, "max_price"); err != nil {
+		return input, err
+	}
+	if input.CreatedFrom, err = parseTimeParam(query, "created_from"); err != nil {
+		return input, err
+	}
+	if input.CreatedTo, err = parseTimeParam(query, "created_to"); err != nil {
+		return input, err
+	}
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		input.Descending = true
+	default:
+		return input, fmt.Errorf("invalid order: %q", query.Get("order"))
+	}
+	return input, nil
+}
+
+func parseInt64Param(query url.Values, name string) (*int64, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return &n, nil
+}
+
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	// the database compares created_at as stored, in UTC
+	t = t.UTC()
+	return &t, nil
 }
@@ -0,0 +1,25 @@
+package web
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenATimeWithAnOffset_WhenParsedAsAFilter_ThenShouldBeInUTC(t *testing.T) {
+	query := url.Values{"created_from": {"2024-05-01T09:00:00-03:00"}}
+
+	from, err := parseTimeParam(query, "created_from")
+
+	require.NoError(t, err)
+	require.NotNil(t, from)
+	assert.Equal(t, time.UTC, from.Location())
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), *from)
+
+	to, err := parseTimeParam(query, "created_to")
+	assert.NoError(t, err)
+	assert.Nil(t, to)
+}
@@ -0,0 +1,64 @@
+package web
+
+import (
+	"context"
+	"net/http"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/usecase"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type WebOrderStatusHandler struct {
+	PayOrderUseCase     *usecase.PayOrderUseCase
+	ShipOrderUseCase    *usecase.ShipOrderUseCase
+	DeliverOrderUseCase *usecase.DeliverOrderUseCase
+	CancelOrderUseCase  *usecase.CancelOrderUseCase
+	RefundOrderUseCase  *usecase.RefundOrderUseCase
+}
+
+func NewWebOrderStatusHandler(
+	PayOrderUseCase *usecase.PayOrderUseCase,
+	ShipOrderUseCase *usecase.ShipOrderUseCase,
+	DeliverOrderUseCase *usecase.DeliverOrderUseCase,
+	CancelOrderUseCase *usecase.CancelOrderUseCase,
+	RefundOrderUseCase *usecase.RefundOrderUseCase,
+) *WebOrderStatusHandler {
+	return &WebOrderStatusHandler{
+		PayOrderUseCase:     PayOrderUseCase,
+		ShipOrderUseCase:    ShipOrderUseCase,
+		DeliverOrderUseCase: DeliverOrderUseCase,
+		CancelOrderUseCase:  CancelOrderUseCase,
+		RefundOrderUseCase:  RefundOrderUseCase,
+	}
+}
+
+func (h *WebOrderStatusHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	changeOrderStatus(w, r, h.PayOrderUseCase.Execute)
+}
+
+func (h *WebOrderStatusHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	changeOrderStatus(w, r, h.ShipOrderUseCase.Execute)
+}
+
+func (h *WebOrderStatusHandler) Deliver(w http.ResponseWriter, r *http.Request) {
+	changeOrderStatus(w, r, h.DeliverOrderUseCase.Execute)
+}
+
+func (h *WebOrderStatusHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	changeOrderStatus(w, r, h.CancelOrderUseCase.Execute)
+}
+
+func (h *WebOrderStatusHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	changeOrderStatus(w, r, h.RefundOrderUseCase.Execute)
+}
+
+func changeOrderStatus(w http.ResponseWriter, r *http.Request, execute func(context.Context, usecase.OrderStatusInputDTO) (usecase.OrderOutputDTO, error)) {
+	output, err := execute(r.Context(), usecase.OrderStatusInputDTO{ID: chi.URLParam(r, "id")})
+	if err != nil {
+		apierror.WriteHTTP(w, r, err)
+		return
+	}
+	writeJSON(w, output)
+}
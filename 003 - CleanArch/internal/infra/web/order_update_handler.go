@@ -0,0 +1,114 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/usecase"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type WebOrderUpdateHandler struct {
+	UpdateOrderUseCase *usecase.UpdateOrderUseCase
+	DeleteOrderUseCase *usecase.DeleteOrderUseCase
+}
+
+func NewWebOrderUpdateHandler(
+	UpdateOrderUseCase *usecase.UpdateOrderUseCase,
+	DeleteOrderUseCase *usecase.DeleteOrderUseCase,
+) *WebOrderUpdateHandler {
+	return &WebOrderUpdateHandler{
+		UpdateOrderUseCase: UpdateOrderUseCase,
+		DeleteOrderUseCase: DeleteOrderUseCase,
+	}
+}
+
+// Update replaces the currency and items of a pending order.
+func (h *WebOrderUpdateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, true)
+}
+
+// Patch changes only the fields present in the body.
+func (h *WebOrderUpdateHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, false)
+}
+
+func (h *WebOrderUpdateHandler) update(w http.ResponseWriter, r *http.Request, replace bool) {
+	var dto usecase.UpdateOrderInputDTO
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		apierror.WriteHTTP(w, r, apierror.Malformed(err))
+		return
+	}
+	if replace {
+		if err := requireReplacement(dto); err != nil {
+			apierror.WriteHTTP(w, r, err)
+			return
+		}
+	}
+	dto.ID = chi.URLParam(r, "id")
+	if dto.Version, err = requestVersion(r, dto.Version); err != nil {
+		apierror.WriteHTTP(w, r, apierror.Malformed(err))
+		return
+	}
+
+	output, err := h.UpdateOrderUseCase.Execute(r.Context(), dto)
+	if err != nil {
+		apierror.WriteHTTP(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(output.Version, 10)))
+	writeJSON(w, output)
+}
+
+func (h *WebOrderUpdateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	version, err := requestVersion(r, 0)
+	if err != nil {
+		apierror.WriteHTTP(w, r, apierror.Malformed(err))
+		return
+	}
+	_, err = h.DeleteOrderUseCase.Execute(r.Context(), usecase.DeleteOrderInputDTO{
+		ID:      chi.URLParam(r, "id"),
+		Version: version,
+	})
+	if err != nil {
+		apierror.WriteHTTP(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireReplacement checks that a PUT body carries every replaceable field.
+func requireReplacement(dto usecase.UpdateOrderInputDTO) error {
+	var errs entity.ValidationError
+	if dto.Currency == "" {
+		errs.Add("currency", entity.ErrRequired)
+	}
+	if len(dto.Items) == 0 {
+		errs.Add("items", entity.ErrRequired)
+	}
+	return errs.Err()
+}
+
+// requestVersion reads the expected order version from the If-Match header,
+// falling back to the ?version query parameter and then to the body value.
+func requestVersion(r *http.Request, bodyVersion int64) (int64, error) {
+	v := strings.TrimPrefix(r.Header.Get("If-Match"), "W/")
+	if v == "" {
+		v = r.URL.Query().Get("version")
+	}
+	if v == "" {
+		return bodyVersion, nil
+	}
+	version, err := strconv.ParseInt(strings.Trim(v, `"`), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,25 @@
+package web
+
+import (
+	"net/http"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/internal/usecase"
+)
+
+type WebOutboxHandler struct {
+	GetOutboxStatusUseCase *usecase.GetOutboxStatusUseCase
+}
+
+func NewWebOutboxHandler(GetOutboxStatusUseCase *usecase.GetOutboxStatusUseCase) *WebOutboxHandler {
+	return &WebOutboxHandler{GetOutboxStatusUseCase: GetOutboxStatusUseCase}
+}
+
+func (h *WebOutboxHandler) Status(w http.ResponseWriter, r *http.Request) {
+	output, err := h.GetOutboxStatusUseCase.Execute(r.Context())
+	if err != nil {
+		apierror.WriteHTTP(w, r, err)
+		return
+	}
+	writeJSON(w, output)
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"CleanArch/internal/infra/web/openapi"
+	"CleanArch/internal/infra/web/webserver"
+)
+
+// OrderAPI serves the REST API. Its /v1 routes are the ones described by
+// openapi.Spec.
+type OrderAPI struct {
+	Orders  *WebOrderHandler
+	Updates *WebOrderUpdateHandler
+	Status  *WebOrderStatusHandler
+	Outbox  *WebOutboxHandler
+}
+
+func NewOrderAPI(
+	Orders *WebOrderHandler,
+	Updates *WebOrderUpdateHandler,
+	Status *WebOrderStatusHandler,
+	Outbox *WebOutboxHandler,
+) *OrderAPI {
+	return &OrderAPI{Orders: Orders, Updates: Updates, Status: Status, Outbox: Outbox}
+}
+
+// Routes lists every /v1 operation. The contract test fails when
+// openapi.Spec describes a different set.
+func (a *OrderAPI) Routes() []webserver.Route {
+	return []webserver.Route{
+		{Method: http.MethodPost, Path: "/v1/orders", Handler: a.Orders.Create},
+		{Method: http.MethodGet, Path: "/v1/orders", Handler: a.Orders.List},
+		{Method: http.MethodGet, Path: "/v1/orders/{id}", Handler: a.Orders.Get},
+		{Method: http.MethodPut, Path: "/v1/orders/{id}", Handler: a.Updates.Update},
+		{Method: http.MethodPatch, Path: "/v1/orders/{id}", Handler: a.Updates.Patch},
+		{Method: http.MethodDelete, Path: "/v1/orders/{id}", Handler: a.Updates.Delete},
+		{Method: http.MethodPost, Path: "/v1/orders/{id}/pay", Handler: a.Status.Pay},
+		{Method: http.MethodPost, Path: "/v1/orders/{id}/ship", Handler: a.Status.Ship},
+		{Method: http.MethodPost, Path: "/v1/orders/{id}/deliver", Handler: a.Status.Deliver},
+		{Method: http.MethodPost, Path: "/v1/orders/{id}/cancel", Handler: a.Status.Cancel},
+		{Method: http.MethodPost, Path: "/v1/orders/{id}/refund", Handler: a.Status.Refund},
+		{Method: http.MethodGet, Path: "/v1/outbox/status", Handler: a.Outbox.Status},
+	}
+}
+
+// LegacyRoutes are the unversioned paths served before /v1, kept for
+// existing clients. They are not validated against the spec, and their
+// responses carry a Deprecation header.
+func (a *OrderAPI) LegacyRoutes() []webserver.Route {
+	routes := []webserver.Route{
+		{Method: http.MethodPost, Path: "/order", Handler: a.Orders.Create},
+		{Method: http.MethodGet, Path: "/orders", Handler: a.Orders.List},
+		{Method: http.MethodGet, Path: "/order/{id}", Handler: a.Orders.Get},
+		{Method: http.MethodPut, Path: "/order/{id}", Handler: a.Updates.Update},
+		{Method: http.MethodPatch, Path: "/order/{id}", Handler: a.Updates.Patch},
+		{Method: http.MethodDelete, Path: "/order/{id}", Handler: a.Updates.Delete},
+		{Method: http.MethodPost, Path: "/order/{id}/pay", Handler: a.Status.Pay},
+		{Method: http.MethodPost, Path: "/order/{id}/ship", Handler: a.Status.Ship},
+		{Method: http.MethodPost, Path: "/order/{id}/deliver", Handler: a.Status.Deliver},
+		{Method: http.MethodPost, Path: "/order/{id}/cancel", Handler: a.Status.Cancel},
+		{Method: http.MethodPost, Path: "/order/{id}/refund", Handler: a.Status.Refund},
+		{Method: http.MethodGet, Path: "/outbox/status", Handler: a.Outbox.Status},
+	}
+	for i, route := range routes {
+		handler := route.Handler
+		routes[i].Handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			handler(w, r)
+		}
+	}
+	return routes
+}
+
+// ValidatedRoutes wraps every route with doc's request validation. It fails
+// if doc lacks the operation of any route.
+func ValidatedRoutes(doc *openapi.Document, routes []webserver.Route) ([]webserver.Route, error) {
+	validated := make([]webserver.Route, 0, len(routes))
+	for _, route := range routes {
+		validate, err := doc.Validate(route.Method, route.Path)
+		if err != nil {
+			return nil, err
+		}
+		route.Handler = validate(route.Handler).ServeHTTP
+		validated = append(validated, route)
+	}
+	return validated, nil
+}
+
+// ServeSpec serves the OpenAPI document.
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapi.Spec)
+}
+
+// writeJSON encodes v as the response body.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
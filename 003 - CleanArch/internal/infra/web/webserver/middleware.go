@@ -0,0 +1,150 @@
+package webserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/pkg/events"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+)
+
+// Config is the middleware stack of a WebServer. The zero value logs,
+// assigns request IDs and recovers from panics, and nothing else.
+type Config struct {
+	// CORS is off while AllowedOrigins is empty.
+	CORS CORSConfig
+	// CompressionLevel, from 1 to 9, compresses JSON responses with brotli
+	// or gzip, as the client accepts; 0 disables compression.
+	CompressionLevel int
+	// MaxBodyBytes rejects larger request bodies with 413; 0 means no limit.
+	MaxBodyBytes int64
+	// TrustProxy takes the client address from X-Forwarded-For or
+	// X-Real-IP. Enable it only behind a proxy that sets them.
+	TrustProxy bool
+	// RequestTimeout bounds every route, and RouteTimeouts the ones listed
+	// by method and pattern, such as "POST /v1/orders". 0 means no deadline.
+	RequestTimeout time.Duration
+	RouteTimeouts  map[string]time.Duration
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS answers preflight requests and adds the CORS headers for the allowed
+// origins. It must run before authentication, as preflights carry no token.
+func CORS(config CORSConfig) func(http.Handler) http.Handler {
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   config.AllowedOrigins,
+		AllowedMethods:   config.AllowedMethods,
+		AllowedHeaders:   config.AllowedHeaders,
+		ExposedHeaders:   config.ExposedHeaders,
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           int(config.MaxAge / time.Second),
+	})
+}
+
+// Compress prefers brotli over gzip and deflate.
+func Compress(level int) func(http.Handler) http.Handler {
+	compressor := middleware.NewCompressor(level, "application/json", "application/problem+json", "text/plain")
+	compressor.SetEncoder("br", func(w io.Writer, level int) io.Writer {
+		return brotli.NewWriterLevel(w, level)
+	})
+	return compressor.Handler
+}
+
+// RequestID keeps the client's X-Request-Id or generates one, and echoes it
+// in the response so clients can quote it. The logger prints it, and the
+// events the request raises carry it as their correlation ID.
+func RequestID(next http.Handler) http.Handler {
+	return middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.GetReqID(r.Context())
+		w.Header().Set(middleware.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(events.WithCorrelationID(r.Context(), id)))
+	}))
+}
+
+// Recover turns a panic into a 500 problem details response and logs the
+// stack. http.ErrAbortHandler is re-raised, as net/http expects.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			log.Printf("panic: %v\n%s", rec, debug.Stack())
+			apierror.WriteHTTP(w, r, fmt.Errorf("panic: %v", rec))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Timeout sets a deadline on the request context. Handlers pass it down to
+// the use cases, whose context errors become 504; when a handler returns
+// without writing anything after the deadline, Timeout writes the 504.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+			if ww.Status() == 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				apierror.WriteHTTP(w, r, ctx.Err())
+			}
+		})
+	}
+}
+
+// middlewares is the stack every request goes through, outermost first.
+func (c Config) middlewares() []func(http.Handler) http.Handler {
+	middlewares := []func(http.Handler) http.Handler{RequestID}
+	if c.TrustProxy {
+		middlewares = append(middlewares, middleware.RealIP)
+	}
+	middlewares = append(middlewares, middleware.Logger, Recover)
+	if len(c.CORS.AllowedOrigins) > 0 {
+		middlewares = append(middlewares, CORS(c.CORS))
+	}
+	if c.CompressionLevel > 0 {
+		middlewares = append(middlewares, Compress(c.CompressionLevel))
+	}
+	if c.MaxBodyBytes > 0 {
+		middlewares = append(middlewares, middleware.RequestSize(c.MaxBodyBytes))
+	}
+	return middlewares
+}
+
+// timeout is the deadline of the route for method and path.
+func (c Config) timeout(method, path string) time.Duration {
+	if timeout, ok := c.RouteTimeouts[method+" "+path]; ok {
+		return timeout
+	}
+	return c.RequestTimeout
+}
+
+func (c Config) withTimeout(method, path string, handler http.Handler) http.Handler {
+	if timeout := c.timeout(method, path); timeout > 0 {
+		return Timeout(timeout)(handler)
+	}
+	return handler
+}
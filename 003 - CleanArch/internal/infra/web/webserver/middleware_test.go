@@ -0,0 +1,144 @@
+package webserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"CleanArch/internal/infra/apierror"
+	"CleanArch/pkg/events"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(s *WebServer, r *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, r)
+	return recorder
+}
+
+func decodeProblem(t *testing.T, recorder *httptest.ResponseRecorder) apierror.ProblemDetails {
+	t.Helper()
+	assert.Equal(t, apierror.ProblemContentType, recorder.Header().Get("Content-Type"))
+	var problem apierror.ProblemDetails
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+	return problem
+}
+
+func TestGivenAPanickingHandler_WhenServed_ThenShouldReturnAJSONProblem(t *testing.T) {
+	s := NewWebServer(":0")
+	s.AddRoute(http.MethodGet, "/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	recorder := serve(s, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	problem := decodeProblem(t, recorder)
+	assert.Equal(t, "internal error", problem.Detail)
+	assert.NotEmpty(t, recorder.Header().Get("X-Request-Id"))
+}
+
+func TestGivenARequestID_WhenServed_ThenShouldEchoAndCorrelateIt(t *testing.T) {
+	s := NewWebServer(":0")
+	var correlationID string
+	s.AddRoute(http.MethodGet, "/", func(w http.ResponseWriter, r *http.Request) {
+		correlationID = events.CorrelationID(r.Context())
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "abc-123")
+
+	recorder := serve(s, r)
+
+	assert.Equal(t, "abc-123", recorder.Header().Get("X-Request-Id"))
+	assert.Equal(t, "abc-123", correlationID)
+}
+
+func TestGivenCORS_WhenPreflighted_ThenShouldAnswerBeforeTheOtherMiddlewares(t *testing.T) {
+	s := NewWebServer(":0")
+	s.Config.CORS = CORSConfig{
+		AllowedOrigins: []string{"https://shop.example"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:         10 * time.Minute,
+	}
+	s.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	})
+	s.AddRoute(http.MethodPost, "/v1/orders", func(w http.ResponseWriter, r *http.Request) {})
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/v1/orders", nil)
+	preflight.Header.Set("Origin", "https://shop.example")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	preflight.Header.Set("Access-Control-Request-Headers", "Authorization")
+	recorder := serve(s, preflight)
+
+	assert.Less(t, recorder.Code, 300)
+	assert.Equal(t, "https://shop.example", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "600", recorder.Header().Get("Access-Control-Max-Age"))
+
+	preflight.Header.Set("Origin", "https://evil.example")
+	recorder = serve(s, preflight)
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestGivenCompression_WhenTheClientAcceptsBrotli_ThenShouldPreferIt(t *testing.T) {
+	s := NewWebServer(":0")
+	s.Config.CompressionLevel = 5
+	body := strings.Repeat(`{"id":"a","status":"pending"},`, 100)
+	s.AddRoute(http.MethodGet, "/v1/orders", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	})
+	r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+	r.Header.Set("Accept-Encoding", "gzip, br")
+
+	recorder := serve(s, r)
+
+	assert.Equal(t, "br", recorder.Header().Get("Content-Encoding"))
+	decoded, err := io.ReadAll(brotli.NewReader(recorder.Body))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestGivenABodyLimit_WhenTheBodyIsLarger_ThenShouldReturn413(t *testing.T) {
+	s := NewWebServer(":0")
+	s.Config.MaxBodyBytes = 16
+	s.AddRoute(http.MethodPost, "/v1/orders", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			apierror.WriteHTTP(w, r, apierror.Malformed(err))
+		}
+	})
+
+	recorder := serve(s, httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(`{"id":"`+strings.Repeat("a", 64)+`"}`)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Equal(t, "the request body exceeds 16 bytes", decodeProblem(t, recorder).Detail)
+}
+
+func TestGivenARouteTimeout_WhenTheHandlerOutlivesIt_ThenShouldReturn504(t *testing.T) {
+	s := NewWebServer(":0")
+	s.Config.RequestTimeout = time.Minute
+	s.Config.RouteTimeouts = map[string]time.Duration{"GET /slow": 10 * time.Millisecond}
+	var deadline time.Time
+	s.AddRoute(http.MethodGet, "/slow", func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ = r.Context().Deadline()
+		<-r.Context().Done()
+	})
+
+	start := time.Now()
+	recorder := serve(s, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.WithinDuration(t, start.Add(10*time.Millisecond), deadline, 5*time.Millisecond)
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	assert.Equal(t, "Timeout", decodeProblem(t, recorder).Title)
+}
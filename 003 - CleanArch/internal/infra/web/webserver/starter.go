@@ -1,10 +1,10 @@
 package webserver
 
 type WebServerStarter struct {
-	WebServer WebServer
+	WebServer *WebServer
 }
 
-func NewWebServerStarter(webServer WebServer) *WebServerStarter {
+func NewWebServerStarter(webServer *WebServer) *WebServerStarter {
 	return &WebServerStarter{
 		WebServer: webServer,
 	}
@@ -2,15 +2,34 @@ package webserver
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type WebServer struct {
 	Router        chi.Router
 	Handlers      map[string]http.HandlerFunc
+	Routes        []Route
+	Middlewares   []func(http.Handler) http.Handler
 	WebServerPort string
+	// Config is read once, when the handler is built.
+	Config Config
+
+	once    sync.Once
+	handler http.Handler
+}
+
+// Route is a handler bound to a single HTTP method. Public routes skip the
+// middlewares added with Use, such as authentication.
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+	Public  bool
 }
 
 func NewWebServer(serverPort string) *WebServer {
@@ -25,13 +44,70 @@ func (s *WebServer) AddHandler(path string, handler http.HandlerFunc) {
 	s.Handlers[path] = handler
 }
 
+func (s *WebServer) AddRoute(method string, path string, handler http.HandlerFunc) {
+	s.Routes = append(s.Routes, Route{Method: method, Path: path, Handler: handler})
+}
+
+func (s *WebServer) AddRoutes(routes ...Route) {
+	s.Routes = append(s.Routes, routes...)
+}
+
+// Use appends middlewares that run, in order, after the Config stack and
+// before every handler that is not public.
+func (s *WebServer) Use(middlewares ...func(http.Handler) http.Handler) {
+	s.Middlewares = append(s.Middlewares, middlewares...)
+}
+
+// Start serves Handler on WebServerPort.
+func (s *WebServer) Start() error {
+	return http.ListenAndServe(s.WebServerPort, s.Handler())
+}
+
+// Handler registers the middlewares, handlers and routes on the router the
+// first time it is called and returns it, traced. Add everything before.
+func (s *WebServer) Handler() http.Handler {
+	s.once.Do(s.mount)
+	return s.handler
+}
+
 // loop through the handlers and add them to the router
 // register middeleware logger
-// start the server
-func (s *WebServer) Start() {
-	s.Router.Use(middleware.Logger)
-	for path, handler := range s.Handlers {
-		s.Router.Handle(path, handler)
+func (s *WebServer) mount() {
+	s.Router.Use(s.Config.middlewares()...)
+	s.Router.Use(routeTag)
+	s.Router.Group(func(r chi.Router) {
+		r.Use(s.Middlewares...)
+		for path, handler := range s.Handlers {
+			r.Handle(path, s.Config.withTimeout("", path, handler))
+		}
+		for _, route := range s.Routes {
+			if !route.Public {
+				r.Method(route.Method, route.Path, s.Config.withTimeout(route.Method, route.Path, route.Handler))
+			}
+		}
+	})
+	for _, route := range s.Routes {
+		if route.Public {
+			s.Router.Method(route.Method, route.Path, s.Config.withTimeout(route.Method, route.Path, route.Handler))
+		}
 	}
-	http.ListenAndServe(s.WebServerPort, s.Router)
+	s.handler = otelhttp.NewHandler(s.Router, "http.server")
+}
+
+// routeTag names the request span after the matched chi route, such as
+// "GET /order/{id}", and adds the route to the HTTP server metrics. The
+// pattern is only known once routing is done, so it runs after next.
+func routeTag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		pattern := chi.RouteContext(r.Context()).RoutePattern()
+		if pattern == "" {
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + pattern)
+		span.SetAttributes(semconv.HTTPRoute(pattern))
+		labeler, _ := otelhttp.LabelerFromContext(r.Context())
+		labeler.Add(semconv.HTTPRoute(pattern))
+	})
 }
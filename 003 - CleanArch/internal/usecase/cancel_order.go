@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+type CancelOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderCancelled  events.EventFactory
+	EventDispatcher events.EventDispatcherInterface
+	Timeout         time.Duration
+}
+
+func NewCancelOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderCancelled events.EventFactory,
+	EventDispatcher events.EventDispatcherInterface,
+) *CancelOrderUseCase {
+	return &CancelOrderUseCase{
+		OrderRepository: OrderRepository,
+		OrderCancelled:  OrderCancelled,
+		EventDispatcher: EventDispatcher,
+	}
+}
+
+func (c *CancelOrderUseCase) Execute(ctx context.Context, input OrderStatusInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "CancelOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	return changeOrderStatus(ctx, c.OrderRepository, c.OrderCancelled, c.EventDispatcher, input, (*entity.Order).Cancel)
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+type OrderStatusInputDTO struct {
+	ID string `json:"id"`
+}
+
+// changeOrderStatus loads the order, applies the transition, persists it with
+// the event that matches the new status in the outbox and dispatches it.
+func changeOrderStatus(
+	ctx context.Context,
+	repository entity.OrderRepositoryInterface,
+	newEvent events.EventFactory,
+	dispatcher events.EventDispatcherInterface,
+	input OrderStatusInputDTO,
+	transition func(order *entity.Order) error,
+) (OrderOutputDTO, error) {
+	order, err := repository.FindByID(ctx, input.ID)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+	if err := transition(order); err != nil {
+		return OrderOutputDTO{}, err
+	}
+
+	// the event carries the version the update is about to store
+	dto := NewOrderOutputDTO(order)
+	dto.Version++
+
+	event := newEvent()
+	event.SetPayload(dto)
+	event.SetMetadata(newEventMetadata(ctx, order.ID))
+	message, err := outboxMessage(order.ID, event)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+	if err := repository.Update(ctx, order, message); err != nil {
+		return OrderOutputDTO{}, err
+	}
+
+	dispatchEvent(ctx, dispatcher, event)
+
+	return dto, nil
+}
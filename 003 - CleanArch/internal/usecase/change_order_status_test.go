@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenAPendingOrder_WhenPaid_ThenShouldPersistTheStatusWithItsEvent(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, dispatched := recordingDispatcher(t, "OrderPaid")
+	uc := NewPayOrderUseCase(repository, event.NewOrderPaidFactory(), dispatcher)
+
+	output, err := uc.Execute(context.Background(), OrderStatusInputDTO{ID: "a"})
+
+	require.NoError(t, err)
+	assert.Equal(t, string(entity.OrderStatusPaid), output.Status)
+	assert.Equal(t, int64(2), output.Version)
+	assert.NotNil(t, output.PaidAt)
+	assert.Equal(t, entity.OrderStatusPaid, repository.orders["a"].Status)
+
+	require.Len(t, repository.outbox, 1)
+	ce := outboxEvent(t, repository.outbox[0])
+	assert.Equal(t, "OrderPaid", repository.outbox[0].EventName)
+	var payload OrderOutputDTO
+	require.NoError(t, json.Unmarshal(ce.Data, &payload))
+	assert.Equal(t, int64(2), payload.Version, "the event carries the stored version")
+	require.Len(t, *dispatched, 1)
+	assert.Equal(t, repository.outbox[0].ID, (*dispatched)[0].GetMetadata().ID)
+}
+
+func TestGivenAPendingOrder_WhenShipped_ThenShouldRejectTheTransition(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, dispatched := recordingDispatcher(t, "OrderShipped")
+	uc := NewShipOrderUseCase(repository, event.NewOrderShippedFactory(), dispatcher)
+
+	_, err := uc.Execute(context.Background(), OrderStatusInputDTO{ID: "a"})
+
+	assert.ErrorIs(t, err, entity.ErrInvalidStatusTransition)
+	assert.Equal(t, entity.OrderStatusPending, repository.orders["a"].Status)
+	assert.Empty(t, repository.outbox)
+	assert.Empty(t, *dispatched)
+}
+
+func TestGivenAnUnknownOrder_WhenCancelled_ThenShouldReturnNotFound(t *testing.T) {
+	dispatcher, _ := recordingDispatcher(t)
+	uc := NewCancelOrderUseCase(newMemoryOrderRepository(), event.NewOrderCancelledFactory(), dispatcher)
+
+	_, err := uc.Execute(context.Background(), OrderStatusInputDTO{ID: "missing"})
+
+	assert.ErrorIs(t, err, entity.ErrOrderNotFound)
+}
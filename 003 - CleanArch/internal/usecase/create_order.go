@@ -1,61 +1,160 @@
 package usecase
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
 	"CleanArch/internal/entity"
 	"CleanArch/pkg/events"
+
+	"github.com/oklog/ulid/v2"
 )
 
-type OrderInputDTO struct {
-	ID    string  `json:"id"`
-	Price float64 `json:"price"`
-	Tax   float64 `json:"tax"`
-}
+const createOrderOperation = "CreateOrder"
 
-type OrderOutputDTO struct {
-	ID         string  `json:"id"`
-	Price      float64 `json:"price"`
-	Tax        float64 `json:"tax"`
-	FinalPrice float64 `json:"final_price"`
-}
+// DefaultIdempotencyTTL is how long a key is remembered unless configured.
+const DefaultIdempotencyTTL = 24 * time.Hour
 
 type CreateOrderUseCase struct {
-	OrderRepository entity.OrderRepositoryInterface
-	OrderCreated    events.EventInterface
-	EventDispatcher events.EventDispatcherInterface
+	OrderRepository       entity.OrderRepositoryInterface
+	IdempotencyRepository entity.IdempotencyRepositoryInterface
+	OrderCreated          events.EventFactory
+	EventDispatcher       events.EventDispatcherInterface
+	Timeout               time.Duration
+	IdempotencyTTL        time.Duration
 }
 
 func NewCreateOrderUseCase(
 	OrderRepository entity.OrderRepositoryInterface,
-	OrderCreated events.EventInterface,
+	IdempotencyRepository entity.IdempotencyRepositoryInterface,
+	OrderCreated events.EventFactory,
 	EventDispatcher events.EventDispatcherInterface,
 ) *CreateOrderUseCase {
 	return &CreateOrderUseCase{
-		OrderRepository: OrderRepository,
-		OrderCreated:    OrderCreated,
-		EventDispatcher: EventDispatcher,
+		OrderRepository:       OrderRepository,
+		IdempotencyRepository: IdempotencyRepository,
+		OrderCreated:          OrderCreated,
+		EventDispatcher:       EventDispatcher,
+		IdempotencyTTL:        DefaultIdempotencyTTL,
 	}
 }
 
-func (c *CreateOrderUseCase) Execute(input OrderInputDTO) (OrderOutputDTO, error) {
+func (c *CreateOrderUseCase) Execute(ctx context.Context, input OrderInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, createOrderOperation)
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	if input.IdempotencyKey == "" || c.IdempotencyRepository == nil {
+		return c.create(ctx, input, nil)
+	}
+	if len(input.IdempotencyKey) > entity.MaxIdempotencyKeyLength {
+		var errs entity.ValidationError
+		errs.Add("idempotency_key", entity.ErrTooLong)
+		return OrderOutputDTO{}, errs.Err()
+	}
+
+	fingerprint, err := fingerprintInput(input)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+	record := entity.NewIdempotencyRecord(createOrderOperation, input.IdempotencyKey, fingerprint, c.IdempotencyTTL)
+	if output, found, err := c.replay(ctx, record); found || err != nil {
+		return output, err
+	}
+	output, err = c.create(ctx, input, &record)
+	if errors.Is(err, entity.ErrIdempotencyKeyTaken) {
+		// a concurrent request with the same key saved its order first
+		output, found, err := c.replay(ctx, record)
+		if err == nil && !found {
+			err = entity.ErrIdempotencyKeyInProgress
+		}
+		return output, err
+	}
+	return output, err
+}
+
+// replay returns the stored response for record's key, if there is one.
+func (c *CreateOrderUseCase) replay(ctx context.Context, record entity.IdempotencyRecord) (OrderOutputDTO, bool, error) {
+	stored, err := c.IdempotencyRepository.Find(ctx, record.Operation, record.Key)
+	if errors.Is(err, entity.ErrIdempotencyRecordNotFound) {
+		return OrderOutputDTO{}, false, nil
+	}
+	if err != nil {
+		return OrderOutputDTO{}, false, err
+	}
+	response, err := stored.Replay(record.Fingerprint)
+	if err != nil {
+		return OrderOutputDTO{}, true, err
+	}
+	var output OrderOutputDTO
+	err = json.Unmarshal(response, &output)
+	return output, true, err
+}
+
+// fingerprintInput hashes the fields that define the request, so a key
+// reused with a different payload, or by another subject, can be told apart.
+func fingerprintInput(input OrderInputDTO) (string, error) {
+	payload, err := json.Marshal(struct {
+		Input     OrderInputDTO `json:"input"`
+		CreatedBy string        `json:"created_by"`
+	}{input, input.CreatedBy})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// create saves the order and its OrderCreated message, and record with the
+// response when the request carries an idempotency key, in one transaction.
+func (c *CreateOrderUseCase) create(ctx context.Context, input OrderInputDTO, record *entity.IdempotencyRecord) (OrderOutputDTO, error) {
+	if input.ID == "" {
+		input.ID = ulid.Make().String()
+	}
+	currency := strings.ToUpper(input.Currency)
 	order := entity.Order{
-		ID:    input.ID,
-		Price: input.Price,
-		Tax:   input.Tax,
+		ID:        input.ID,
+		Currency:  currency,
+		Items:     newOrderItems(currency, input.Items),
+		Status:    entity.OrderStatusPending,
+		CreatedAt: time.Now().UTC(),
+		Version:   1,
+		CreatedBy: input.CreatedBy,
 	}
-	order.CalculateFinalPrice()
-	if err := c.OrderRepository.Save(&order); err != nil {
+	if err := order.CalculateFinalPrice(); err != nil {
 		return OrderOutputDTO{}, err
 	}
 
-	dto := OrderOutputDTO{
-		ID:         order.ID,
-		Price:      order.Price,
-		Tax:        order.Tax,
-		FinalPrice: order.Price + order.Tax,
+	dto := NewOrderOutputDTO(&order)
+
+	orderCreated := c.OrderCreated()
+	orderCreated.SetPayload(dto)
+	orderCreated.SetMetadata(newEventMetadata(ctx, order.ID))
+
+	message, err := outboxMessage(order.ID, orderCreated)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+	if record == nil {
+		err = c.OrderRepository.SaveWithOutbox(ctx, &order, message)
+	} else {
+		record.Response, err = json.Marshal(dto)
+		if err != nil {
+			return OrderOutputDTO{}, err
+		}
+		err = c.OrderRepository.SaveWithIdempotency(ctx, &order, *record, message)
+	}
+	if err != nil {
+		return OrderOutputDTO{}, err
 	}
 
-	c.OrderCreated.SetPayload(dto)
-	c.EventDispatcher.Dispatch(c.OrderCreated)
+	dispatchEvent(ctx, c.EventDispatcher, orderCreated)
 
 	return dto, nil
 }
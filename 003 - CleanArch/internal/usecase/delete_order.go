@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+type DeleteOrderInputDTO struct {
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+}
+
+type DeleteOrderOutputDTO struct {
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+}
+
+type DeleteOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderDeleted    events.EventFactory
+	EventDispatcher events.EventDispatcherInterface
+	Timeout         time.Duration
+}
+
+func NewDeleteOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderDeleted events.EventFactory,
+	EventDispatcher events.EventDispatcherInterface,
+) *DeleteOrderUseCase {
+	return &DeleteOrderUseCase{
+		OrderRepository: OrderRepository,
+		OrderDeleted:    OrderDeleted,
+		EventDispatcher: EventDispatcher,
+	}
+}
+
+func (c *DeleteOrderUseCase) Execute(ctx context.Context, input DeleteOrderInputDTO) (output DeleteOrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "DeleteOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	if input.Version <= 0 {
+		return DeleteOrderOutputDTO{}, ErrVersionRequired
+	}
+
+	dto := DeleteOrderOutputDTO{ID: input.ID, Version: input.Version}
+
+	orderDeleted := c.OrderDeleted()
+	orderDeleted.SetPayload(dto)
+	orderDeleted.SetMetadata(newEventMetadata(ctx, input.ID))
+	message, err := outboxMessage(input.ID, orderDeleted)
+	if err != nil {
+		return DeleteOrderOutputDTO{}, err
+	}
+	if err := c.OrderRepository.Delete(ctx, input.ID, input.Version, message); err != nil {
+		return DeleteOrderOutputDTO{}, err
+	}
+
+	dispatchEvent(ctx, c.EventDispatcher, orderDeleted)
+
+	return dto, nil
+}
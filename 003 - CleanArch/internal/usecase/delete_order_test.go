@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenAnOrder_WhenDeleted_ThenShouldRemoveItAndWriteTheEvent(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, dispatched := recordingDispatcher(t, "OrderDeleted")
+	uc := NewDeleteOrderUseCase(repository, event.NewOrderDeletedFactory(), dispatcher)
+
+	output, err := uc.Execute(context.Background(), DeleteOrderInputDTO{ID: "a", Version: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, DeleteOrderOutputDTO{ID: "a", Version: 1}, output)
+	assert.NotContains(t, repository.orders, "a")
+	require.Len(t, repository.outbox, 1)
+	assert.Equal(t, "OrderDeleted", repository.outbox[0].EventName)
+	var payload DeleteOrderOutputDTO
+	require.NoError(t, json.Unmarshal(outboxEvent(t, repository.outbox[0]).Data, &payload))
+	assert.Equal(t, output, payload)
+	assert.Len(t, *dispatched, 1)
+}
+
+func TestGivenAStaleVersion_WhenDeleted_ThenShouldKeepTheOrder(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, dispatched := recordingDispatcher(t, "OrderDeleted")
+	uc := NewDeleteOrderUseCase(repository, event.NewOrderDeletedFactory(), dispatcher)
+
+	_, err := uc.Execute(context.Background(), DeleteOrderInputDTO{ID: "a", Version: 2})
+	assert.ErrorIs(t, err, entity.ErrVersionConflict)
+
+	_, err = uc.Execute(context.Background(), DeleteOrderInputDTO{ID: "a"})
+	assert.ErrorIs(t, err, ErrVersionRequired)
+
+	_, err = uc.Execute(context.Background(), DeleteOrderInputDTO{ID: "missing", Version: 1})
+	assert.ErrorIs(t, err, entity.ErrOrderNotFound)
+
+	assert.Contains(t, repository.orders, "a")
+	assert.Empty(t, repository.outbox)
+	assert.Empty(t, *dispatched)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+type DeliverOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderDelivered  events.EventFactory
+	EventDispatcher events.EventDispatcherInterface
+	Timeout         time.Duration
+}
+
+func NewDeliverOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderDelivered events.EventFactory,
+	EventDispatcher events.EventDispatcherInterface,
+) *DeliverOrderUseCase {
+	return &DeliverOrderUseCase{
+		OrderRepository: OrderRepository,
+		OrderDelivered:  OrderDelivered,
+		EventDispatcher: EventDispatcher,
+	}
+}
+
+func (c *DeliverOrderUseCase) Execute(ctx context.Context, input OrderStatusInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "DeliverOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	return changeOrderStatus(ctx, c.OrderRepository, c.OrderDelivered, c.EventDispatcher, input, (*entity.Order).Deliver)
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+// outboxMessage wraps event in a CloudEvent for the outbox. The brokers are
+// fed from the outbox, written atomically with the order, so the event
+// survives a broker outage or a crash right after the commit.
+func outboxMessage(aggregateID string, event events.EventInterface) (entity.OutboxMessage, error) {
+	payload, err := events.MarshalCloudEvent(event)
+	if err != nil {
+		return entity.OutboxMessage{}, err
+	}
+	return entity.NewOutboxMessage(event.GetMetadata().ID, aggregateID, event.GetName(), payload), nil
+}
+
+// dispatchEvent notifies in-process handlers once the change is committed.
+// Their failures are logged rather than returned: the order is already
+// persisted and brokers are fed from the outbox, not from these handlers.
+func dispatchEvent(ctx context.Context, dispatcher events.EventDispatcherInterface, event events.EventInterface) {
+	if err := dispatcher.Dispatch(ctx, event); err != nil {
+		log.Printf("usecase: dispatching %s: %v", event.GetName(), err)
+	}
+}
+
+// withTimeout bounds an operation by its configured timeout; zero means the
+// caller's deadline, if any, is the only limit.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,14 @@
+package usecase
+
+// Payload types of the order events, for typed handlers registered with
+// events.Register, e.g. events.Register[usecase.OrderCreatedPayload].
+type (
+	OrderCreatedPayload   = OrderOutputDTO
+	OrderUpdatedPayload   = OrderOutputDTO
+	OrderPaidPayload      = OrderOutputDTO
+	OrderShippedPayload   = OrderOutputDTO
+	OrderDeliveredPayload = OrderOutputDTO
+	OrderCancelledPayload = OrderOutputDTO
+	OrderRefundedPayload  = OrderOutputDTO
+	OrderDeletedPayload   = DeleteOrderOutputDTO
+)
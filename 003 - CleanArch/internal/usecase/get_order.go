@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+)
+
+type GetOrderInputDTO struct {
+	ID string `json:"id"`
+}
+
+type GetOrdersInputDTO struct {
+	IDs []string `json:"ids"`
+}
+
+type GetOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	Timeout         time.Duration
+}
+
+func NewGetOrderUseCase(OrderRepository entity.OrderRepositoryInterface) *GetOrderUseCase {
+	return &GetOrderUseCase{OrderRepository: OrderRepository}
+}
+
+func (uc *GetOrderUseCase) Execute(ctx context.Context, input GetOrderInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "GetOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, uc.Timeout)
+	defer cancel()
+
+	order, err := uc.OrderRepository.FindByID(ctx, input.ID)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+	return NewOrderOutputDTO(order), nil
+}
+
+// ExecuteMany loads the orders with the given IDs in one query, keyed by ID;
+// IDs without an order are missing from the result.
+func (uc *GetOrderUseCase) ExecuteMany(ctx context.Context, input GetOrdersInputDTO) (output map[string]OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "GetOrders")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, uc.Timeout)
+	defer cancel()
+
+	orders, err := uc.OrderRepository.FindByIDs(ctx, input.IDs)
+	if err != nil {
+		return nil, err
+	}
+	output = make(map[string]OrderOutputDTO, len(orders))
+	for i := range orders {
+		output[orders[i].ID] = NewOrderOutputDTO(&orders[i])
+	}
+	return output, nil
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+)
+
+type OutboxStatusOutputDTO struct {
+	Pending         int64      `json:"pending"`
+	Failing         int64      `json:"failing"`
+	Published       int64      `json:"published"`
+	Dead            int64      `json:"dead"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	LagSeconds      float64    `json:"lag_seconds"`
+}
+
+type GetOutboxStatusUseCase struct {
+	OutboxRepository entity.OutboxRepositoryInterface
+	Timeout          time.Duration
+}
+
+func NewGetOutboxStatusUseCase(OutboxRepository entity.OutboxRepositoryInterface) *GetOutboxStatusUseCase {
+	return &GetOutboxStatusUseCase{OutboxRepository: OutboxRepository}
+}
+
+func (uc *GetOutboxStatusUseCase) Execute(ctx context.Context) (output OutboxStatusOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "GetOutboxStatus")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, uc.Timeout)
+	defer cancel()
+
+	stats, err := uc.OutboxRepository.Stats(ctx)
+	if err != nil {
+		return OutboxStatusOutputDTO{}, err
+	}
+	output = OutboxStatusOutputDTO{
+		Pending:         stats.Pending,
+		Failing:         stats.Failing,
+		Published:       stats.Published,
+		Dead:            stats.Dead,
+		OldestPendingAt: stats.OldestPendingAt,
+	}
+	if stats.OldestPendingAt != nil {
+		output.LagSeconds = time.Since(*stats.OldestPendingAt).Seconds()
+	}
+	return output, nil
+}
@@ -1,33 +1,172 @@
 package usecase
 
-import "CleanArch/internal/entity"
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"CleanArch/internal/entity"
+)
+
+const (
+	DefaultOrdersPageSize = 20
+	MaxOrdersPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSortField = errors.New("invalid sort field")
+	ErrInvalidStatus    = errors.New("invalid status")
+)
+
+type ListOrdersInputDTO struct {
+	PageSize    int        `json:"page_size"`
+	After       string     `json:"after"`
+	Status      string     `json:"status"`
+	MinPrice    *int64     `json:"min_price"`
+	MaxPrice    *int64     `json:"max_price"`
+	CreatedFrom *time.Time `json:"created_from"`
+	CreatedTo   *time.Time `json:"created_to"`
+	SortBy      string     `json:"sort_by"`
+	Descending  bool       `json:"descending"`
+}
+
+type OrderEdgeDTO struct {
+	Cursor string         `json:"cursor"`
+	Order  OrderOutputDTO `json:"order"`
+}
+
+type PageInfoDTO struct {
+	EndCursor   string `json:"end_cursor,omitempty"`
+	HasNextPage bool   `json:"has_next_page"`
+}
 
 type ListOrdersOutputDTO struct {
-	Orders []entity.Order
-	Total  int32
+	Edges    []OrderEdgeDTO `json:"edges"`
+	PageInfo PageInfoDTO    `json:"page_info"`
 }
 
 type ListOrdersUseCase struct {
 	OrderRepository entity.OrderRepositoryInterface
+	Timeout         time.Duration
 }
 
 func NewListOrdersUseCase(OrderRepository entity.OrderRepositoryInterface) *ListOrdersUseCase {
 	return &ListOrdersUseCase{OrderRepository: OrderRepository}
 }
 
-func (uc *ListOrdersUseCase) ListOrders() (ListOrdersOutputDTO, error) {
-	orders, err := uc.OrderRepository.List()
+func (uc *ListOrdersUseCase) ListOrders(ctx context.Context, input ListOrdersInputDTO) (output ListOrdersOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "ListOrders")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, uc.Timeout)
+	defer cancel()
+
+	query, err := newOrderListQuery(input)
 	if err != nil {
 		return ListOrdersOutputDTO{}, err
 	}
+	pageSize := query.Limit
+	// one extra row tells whether there is a next page without a count(*)
+	query.Limit++
 
-	total, err := uc.OrderRepository.GetTotal()
+	orders, err := uc.OrderRepository.List(ctx, query)
 	if err != nil {
 		return ListOrdersOutputDTO{}, err
 	}
 
-	return ListOrdersOutputDTO{
-		Orders: orders,
-		Total:  int32(total),
+	output = ListOrdersOutputDTO{Edges: make([]OrderEdgeDTO, 0, pageSize)}
+	if len(orders) > pageSize {
+		orders = orders[:pageSize]
+		output.PageInfo.HasNextPage = true
+	}
+	for _, order := range orders {
+		output.Edges = append(output.Edges, OrderEdgeDTO{
+			Cursor: encodeOrderCursor(query, order),
+			Order:  NewOrderOutputDTO(&order),
+		})
+	}
+	if len(output.Edges) > 0 {
+		output.PageInfo.EndCursor = output.Edges[len(output.Edges)-1].Cursor
+	}
+	return output, nil
+}
+
+func newOrderListQuery(input ListOrdersInputDTO) (entity.OrderListQuery, error) {
+	query := entity.OrderListQuery{
+		Filter: entity.OrderListFilter{
+			Status:      entity.OrderStatus(input.Status),
+			MinPrice:    input.MinPrice,
+			MaxPrice:    input.MaxPrice,
+			CreatedFrom: input.CreatedFrom,
+			CreatedTo:   input.CreatedTo,
+		},
+		SortBy:     entity.OrderSortField(input.SortBy),
+		Descending: input.Descending,
+		Limit:      input.PageSize,
+	}
+	if query.SortBy == "" {
+		query.SortBy = entity.OrderSortByCreatedAt
+	}
+	if !query.SortBy.IsValid() {
+		return entity.OrderListQuery{}, ErrInvalidSortField
+	}
+	if query.Filter.Status != "" && !query.Filter.Status.IsValid() {
+		return entity.OrderListQuery{}, ErrInvalidStatus
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultOrdersPageSize
+	}
+	if query.Limit > MaxOrdersPageSize {
+		query.Limit = MaxOrdersPageSize
+	}
+	if input.After != "" {
+		cursor, err := decodeOrderCursor(query, input.After)
+		if err != nil {
+			return entity.OrderListQuery{}, err
+		}
+		query.After = cursor
+	}
+	return query, nil
+}
+
+// orderCursor is the opaque cursor handed to clients. It records the sort it
+// was issued for so it can't be replayed against a different ordering.
+type orderCursor struct {
+	SortBy     entity.OrderSortField `json:"s"`
+	Descending bool                  `json:"d,omitempty"`
+	CreatedAt  time.Time             `json:"c"`
+	FinalPrice int64                 `json:"p"`
+	ID         string                `json:"id"`
+}
+
+func encodeOrderCursor(query entity.OrderListQuery, order entity.Order) string {
+	data, _ := json.Marshal(orderCursor{
+		SortBy:     query.SortBy,
+		Descending: query.Descending,
+		CreatedAt:  order.CreatedAt,
+		FinalPrice: order.FinalPrice.Amount,
+		ID:         order.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeOrderCursor(query entity.OrderListQuery, value string) (*entity.OrderCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor orderCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy != query.SortBy || cursor.Descending != query.Descending {
+		return nil, ErrInvalidCursor
+	}
+	return &entity.OrderCursor{
+		CreatedAt:  cursor.CreatedAt,
+		FinalPrice: cursor.FinalPrice,
+		ID:         cursor.ID,
 	}, nil
-}
\ No newline at end of file
+}
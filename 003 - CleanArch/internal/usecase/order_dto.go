@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"time"
+
+	"CleanArch/internal/entity"
+)
+
+// MoneyDTO carries an amount in the currency's minor units (cents).
+type MoneyDTO struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type OrderItemInputDTO struct {
+	SKU        string `json:"sku"`
+	Quantity   int64  `json:"quantity"`
+	UnitPrice  int64  `json:"unit_price"`
+	TaxRateBps int64  `json:"tax_rate_bps"`
+}
+
+// OrderInputDTO creates an order. An empty ID is replaced by a ULID. Requests
+// repeated with the same IdempotencyKey, read by the transports from the
+// Idempotency-Key header or metadata, return the first response. CreatedBy
+// is the authenticated subject, also set by the transports.
+type OrderInputDTO struct {
+	ID             string              `json:"id"`
+	Currency       string              `json:"currency"`
+	Items          []OrderItemInputDTO `json:"items"`
+	IdempotencyKey string              `json:"-"`
+	CreatedBy      string              `json:"-"`
+}
+
+type OrderItemOutputDTO struct {
+	SKU        string   `json:"sku"`
+	Quantity   int64    `json:"quantity"`
+	UnitPrice  MoneyDTO `json:"unit_price"`
+	TaxRateBps int64    `json:"tax_rate_bps"`
+	Subtotal   MoneyDTO `json:"subtotal"`
+	Tax        MoneyDTO `json:"tax"`
+}
+
+type OrderOutputDTO struct {
+	ID          string               `json:"id"`
+	Currency    string               `json:"currency"`
+	Items       []OrderItemOutputDTO `json:"items"`
+	Price       MoneyDTO             `json:"price"`
+	Tax         MoneyDTO             `json:"tax"`
+	FinalPrice  MoneyDTO             `json:"final_price"`
+	Status      string               `json:"status"`
+	CreatedAt   time.Time            `json:"created_at"`
+	PaidAt      *time.Time           `json:"paid_at,omitempty"`
+	ShippedAt   *time.Time           `json:"shipped_at,omitempty"`
+	DeliveredAt *time.Time           `json:"delivered_at,omitempty"`
+	CancelledAt *time.Time           `json:"cancelled_at,omitempty"`
+	RefundedAt  *time.Time           `json:"refunded_at,omitempty"`
+	Version     int64                `json:"version"`
+	CreatedBy   string               `json:"created_by,omitempty"`
+}
+
+func NewMoneyDTO(m entity.Money) MoneyDTO {
+	return MoneyDTO{Amount: m.Amount, Currency: m.Currency}
+}
+
+func NewOrderOutputDTO(order *entity.Order) OrderOutputDTO {
+	items := make([]OrderItemOutputDTO, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, OrderItemOutputDTO{
+			SKU:        item.SKU,
+			Quantity:   item.Quantity,
+			UnitPrice:  NewMoneyDTO(item.UnitPrice),
+			TaxRateBps: item.TaxRateBps,
+			Subtotal:   NewMoneyDTO(item.Subtotal()),
+			Tax:        NewMoneyDTO(item.Tax()),
+		})
+	}
+	return OrderOutputDTO{
+		ID:          order.ID,
+		Currency:    order.Currency,
+		Items:       items,
+		Price:       NewMoneyDTO(order.Price),
+		Tax:         NewMoneyDTO(order.Tax),
+		FinalPrice:  NewMoneyDTO(order.FinalPrice),
+		Status:      string(order.Status),
+		CreatedAt:   order.CreatedAt,
+		PaidAt:      order.PaidAt,
+		ShippedAt:   order.ShippedAt,
+		DeliveredAt: order.DeliveredAt,
+		CancelledAt: order.CancelledAt,
+		RefundedAt:  order.RefundedAt,
+		Version:     order.Version,
+		CreatedBy:   order.CreatedBy,
+	}
+}
+
+func newOrderItems(currency string, input []OrderItemInputDTO) []entity.OrderItem {
+	items := make([]entity.OrderItem, 0, len(input))
+	for _, item := range input {
+		items = append(items, entity.OrderItem{
+			SKU:        item.SKU,
+			Quantity:   item.Quantity,
+			UnitPrice:  entity.Money{Amount: item.UnitPrice, Currency: currency},
+			TaxRateBps: item.TaxRateBps,
+		})
+	}
+	return items
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+type PayOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderPaid       events.EventFactory
+	EventDispatcher events.EventDispatcherInterface
+	Timeout         time.Duration
+}
+
+func NewPayOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderPaid events.EventFactory,
+	EventDispatcher events.EventDispatcherInterface,
+) *PayOrderUseCase {
+	return &PayOrderUseCase{
+		OrderRepository: OrderRepository,
+		OrderPaid:       OrderPaid,
+		EventDispatcher: EventDispatcher,
+	}
+}
+
+func (c *PayOrderUseCase) Execute(ctx context.Context, input OrderStatusInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "PayOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	return changeOrderStatus(ctx, c.OrderRepository, c.OrderPaid, c.EventDispatcher, input, (*entity.Order).Pay)
+}
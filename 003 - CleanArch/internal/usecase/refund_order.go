@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+type RefundOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderRefunded   events.EventFactory
+	EventDispatcher events.EventDispatcherInterface
+	Timeout         time.Duration
+}
+
+func NewRefundOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderRefunded events.EventFactory,
+	EventDispatcher events.EventDispatcherInterface,
+) *RefundOrderUseCase {
+	return &RefundOrderUseCase{
+		OrderRepository: OrderRepository,
+		OrderRefunded:   OrderRefunded,
+		EventDispatcher: EventDispatcher,
+	}
+}
+
+func (c *RefundOrderUseCase) Execute(ctx context.Context, input OrderStatusInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "RefundOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	return changeOrderStatus(ctx, c.OrderRepository, c.OrderRefunded, c.EventDispatcher, input, (*entity.Order).Refund)
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOrderRepository keeps orders in a map with the version checks of
+// the database repository, and records the outbox messages written with
+// each change.
+type memoryOrderRepository struct {
+	entity.OrderRepositoryInterface
+	orders map[string]entity.Order
+	outbox []entity.OutboxMessage
+}
+
+func newMemoryOrderRepository(orders ...*entity.Order) *memoryOrderRepository {
+	r := &memoryOrderRepository{orders: map[string]entity.Order{}}
+	for _, order := range orders {
+		r.orders[order.ID] = *order
+	}
+	return r
+}
+
+func (r *memoryOrderRepository) FindByID(_ context.Context, id string) (*entity.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, entity.ErrOrderNotFound
+	}
+	return &order, nil
+}
+
+func (r *memoryOrderRepository) checkVersion(id string, version int64) error {
+	stored, ok := r.orders[id]
+	if !ok {
+		return entity.ErrOrderNotFound
+	}
+	if stored.Version != version {
+		return entity.ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *memoryOrderRepository) Update(_ context.Context, order *entity.Order, messages ...entity.OutboxMessage) error {
+	if err := r.checkVersion(order.ID, order.Version); err != nil {
+		return err
+	}
+	order.Version++
+	r.orders[order.ID] = *order
+	r.outbox = append(r.outbox, messages...)
+	return nil
+}
+
+func (r *memoryOrderRepository) Delete(_ context.Context, id string, version int64, messages ...entity.OutboxMessage) error {
+	if err := r.checkVersion(id, version); err != nil {
+		return err
+	}
+	delete(r.orders, id)
+	r.outbox = append(r.outbox, messages...)
+	return nil
+}
+
+// recordingDispatcher returns a dispatcher and the events it dispatches.
+func recordingDispatcher(t *testing.T, names ...string) (*events.EventDispatcher, *[]events.EventInterface) {
+	dispatched := &[]events.EventInterface{}
+	dispatcher := events.NewEventDispatcher()
+	for _, name := range names {
+		require.NoError(t, dispatcher.Register(name, events.HandlerFunc(func(_ context.Context, event events.EventInterface) error {
+			*dispatched = append(*dispatched, event)
+			return nil
+		})))
+	}
+	return dispatcher, dispatched
+}
+
+func newPendingOrder(t *testing.T, id string) *entity.Order {
+	order, err := entity.NewOrder(id, "BRL", []entity.OrderItem{
+		{SKU: "SKU-1", Quantity: 2, UnitPrice: entity.Money{Amount: 1000, Currency: "BRL"}, TaxRateBps: 1000},
+	})
+	require.NoError(t, err)
+	require.NoError(t, order.CalculateFinalPrice())
+	return order
+}
+
+// outboxEvent decodes the CloudEvent of an outbox message.
+func outboxEvent(t *testing.T, message entity.OutboxMessage) events.CloudEvent {
+	ce, err := events.ParseCloudEvent(message.Payload)
+	require.NoError(t, err)
+	return ce
+}
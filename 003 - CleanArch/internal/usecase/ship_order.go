@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+type ShipOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderShipped    events.EventFactory
+	EventDispatcher events.EventDispatcherInterface
+	Timeout         time.Duration
+}
+
+func NewShipOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderShipped events.EventFactory,
+	EventDispatcher events.EventDispatcherInterface,
+) *ShipOrderUseCase {
+	return &ShipOrderUseCase{
+		OrderRepository: OrderRepository,
+		OrderShipped:    OrderShipped,
+		EventDispatcher: EventDispatcher,
+	}
+}
+
+func (c *ShipOrderUseCase) Execute(ctx context.Context, input OrderStatusInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "ShipOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	return changeOrderStatus(ctx, c.OrderRepository, c.OrderShipped, c.EventDispatcher, input, (*entity.Order).Ship)
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"CleanArch/pkg/events"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "CleanArch/internal/usecase"
+
+var (
+	tracer = otel.Tracer(instrumentationName)
+	meter  = otel.Meter(instrumentationName)
+
+	// RED metrics: rate and errors come from operationRequests by outcome,
+	// duration from operationDuration.
+	operationRequests, _ = meter.Int64Counter("usecase.requests",
+		metric.WithDescription("Use case executions by operation and outcome."))
+	operationDuration, _ = meter.Float64Histogram("usecase.duration",
+		metric.WithDescription("Use case execution time by operation and outcome."),
+		metric.WithUnit("s"))
+)
+
+// operation is a traced and measured use case execution, named like the
+// OPERATION_TIMEOUTS entries, such as "CreateOrder".
+type operation struct {
+	name  string
+	span  trace.Span
+	start time.Time
+}
+
+func startOperation(ctx context.Context, name string) (context.Context, *operation) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attribute.String("usecase.operation", name)))
+	return ctx, &operation{name: name, span: span, start: time.Now()}
+}
+
+// end records the outcome held by *err; defer it with the address of the
+// named error result.
+func (o *operation) end(err *error) {
+	outcome := "ok"
+	if *err != nil {
+		outcome = "error"
+		o.span.RecordError(*err)
+		o.span.SetStatus(codes.Error, (*err).Error())
+	}
+	o.span.End()
+	attributes := metric.WithAttributes(
+		attribute.String("usecase.operation", o.name),
+		attribute.String("outcome", outcome),
+	)
+	operationRequests.Add(context.Background(), 1, attributes)
+	operationDuration.Record(context.Background(), time.Since(o.start).Seconds(), attributes)
+}
+
+// newEventMetadata stamps an event with the correlation ID and trace context
+// of ctx, so the CloudEvent, and the broker message relayed from it, continue
+// the chain and the trace of the request that caused it.
+func newEventMetadata(ctx context.Context, aggregateID string) events.Metadata {
+	metadata := events.NewMetadata(ctx, aggregateID)
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	metadata.TraceParent = carrier.Get("traceparent")
+	metadata.TraceState = carrier.Get("tracestate")
+	return metadata
+}
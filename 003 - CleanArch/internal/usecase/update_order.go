@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"CleanArch/internal/entity"
+	"CleanArch/pkg/events"
+)
+
+var ErrVersionRequired = errors.New("version is required")
+
+// UpdateOrderInputDTO changes a pending order. An empty Currency or Items
+// keeps the current value, which is what PATCH relies on; Version must match
+// the stored version.
+type UpdateOrderInputDTO struct {
+	ID       string              `json:"id"`
+	Version  int64               `json:"version"`
+	Currency string              `json:"currency"`
+	Items    []OrderItemInputDTO `json:"items"`
+}
+
+type UpdateOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderUpdated    events.EventFactory
+	EventDispatcher events.EventDispatcherInterface
+	Timeout         time.Duration
+}
+
+func NewUpdateOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderUpdated events.EventFactory,
+	EventDispatcher events.EventDispatcherInterface,
+) *UpdateOrderUseCase {
+	return &UpdateOrderUseCase{
+		OrderRepository: OrderRepository,
+		OrderUpdated:    OrderUpdated,
+		EventDispatcher: EventDispatcher,
+	}
+}
+
+func (c *UpdateOrderUseCase) Execute(ctx context.Context, input UpdateOrderInputDTO) (output OrderOutputDTO, err error) {
+	ctx, op := startOperation(ctx, "UpdateOrder")
+	defer op.end(&err)
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	if input.Version <= 0 {
+		return OrderOutputDTO{}, ErrVersionRequired
+	}
+	order, err := c.OrderRepository.FindByID(ctx, input.ID)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+	if order.Version != input.Version {
+		return OrderOutputDTO{}, entity.ErrVersionConflict
+	}
+
+	currency := order.Currency
+	if input.Currency != "" {
+		currency = strings.ToUpper(input.Currency)
+	}
+	items := order.Items
+	if len(input.Items) > 0 {
+		items = newOrderItems(currency, input.Items)
+	} else if currency != order.Currency {
+		var errs entity.ValidationError
+		errs.Add("currency", entity.ErrNeedsItems)
+		return OrderOutputDTO{}, errs.Err()
+	}
+	if err := order.Edit(currency, items); err != nil {
+		return OrderOutputDTO{}, err
+	}
+
+	// the event carries the version the update is about to store
+	dto := NewOrderOutputDTO(order)
+	dto.Version++
+
+	orderUpdated := c.OrderUpdated()
+	orderUpdated.SetPayload(dto)
+	orderUpdated.SetMetadata(newEventMetadata(ctx, order.ID))
+	message, err := outboxMessage(order.ID, orderUpdated)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+	if err := c.OrderRepository.Update(ctx, order, message); err != nil {
+		return OrderOutputDTO{}, err
+	}
+
+	dispatchEvent(ctx, c.EventDispatcher, orderUpdated)
+
+	return dto, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"CleanArch/internal/entity"
+	"CleanArch/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenAPendingOrder_WhenUpdated_ThenShouldRepriceAndWriteTheEvent(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, dispatched := recordingDispatcher(t, "OrderUpdated")
+	uc := NewUpdateOrderUseCase(repository, event.NewOrderUpdatedFactory(), dispatcher)
+
+	output, err := uc.Execute(context.Background(), UpdateOrderInputDTO{
+		ID:      "a",
+		Version: 1,
+		Items:   []OrderItemInputDTO{{SKU: "SKU-2", Quantity: 1, UnitPrice: 500}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), output.FinalPrice.Amount)
+	assert.Equal(t, int64(2), output.Version)
+	assert.Equal(t, "SKU-2", repository.orders["a"].Items[0].SKU)
+
+	require.Len(t, repository.outbox, 1)
+	var payload OrderOutputDTO
+	require.NoError(t, json.Unmarshal(outboxEvent(t, repository.outbox[0]).Data, &payload))
+	assert.Equal(t, output, payload)
+	assert.Len(t, *dispatched, 1)
+}
+
+func TestGivenACurrencyWithoutItems_WhenUpdated_ThenShouldRejectTheCurrency(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, dispatched := recordingDispatcher(t, "OrderUpdated")
+	uc := NewUpdateOrderUseCase(repository, event.NewOrderUpdatedFactory(), dispatcher)
+
+	_, err := uc.Execute(context.Background(), UpdateOrderInputDTO{ID: "a", Version: 1, Currency: "usd"})
+
+	var validation *entity.ValidationError
+	require.ErrorAs(t, err, &validation)
+	require.Len(t, validation.Fields, 1)
+	assert.Equal(t, "currency", validation.Fields[0].Field)
+	assert.ErrorIs(t, err, entity.ErrNeedsItems)
+	assert.Equal(t, "BRL", repository.orders["a"].Currency)
+	assert.Empty(t, repository.outbox)
+	assert.Empty(t, *dispatched)
+}
+
+func TestGivenTheSameCurrencyWithoutItems_WhenUpdated_ThenShouldKeepTheItems(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, _ := recordingDispatcher(t)
+	uc := NewUpdateOrderUseCase(repository, event.NewOrderUpdatedFactory(), dispatcher)
+
+	output, err := uc.Execute(context.Background(), UpdateOrderInputDTO{ID: "a", Version: 1, Currency: "brl"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "BRL", output.Currency)
+	assert.Equal(t, int64(2200), output.FinalPrice.Amount)
+}
+
+func TestGivenAStaleVersion_WhenUpdated_ThenShouldReturnConflict(t *testing.T) {
+	repository := newMemoryOrderRepository(newPendingOrder(t, "a"))
+	dispatcher, dispatched := recordingDispatcher(t, "OrderUpdated")
+	uc := NewUpdateOrderUseCase(repository, event.NewOrderUpdatedFactory(), dispatcher)
+
+	_, err := uc.Execute(context.Background(), UpdateOrderInputDTO{ID: "a", Version: 3, Currency: "USD"})
+	assert.ErrorIs(t, err, entity.ErrVersionConflict)
+
+	_, err = uc.Execute(context.Background(), UpdateOrderInputDTO{ID: "a", Currency: "USD"})
+	assert.ErrorIs(t, err, ErrVersionRequired)
+
+	assert.Equal(t, "BRL", repository.orders["a"].Currency)
+	assert.Empty(t, repository.outbox)
+	assert.Empty(t, *dispatched)
+}
+
+func TestGivenAPaidOrder_WhenUpdated_ThenShouldRejectTheEdit(t *testing.T) {
+	order := newPendingOrder(t, "a")
+	require.NoError(t, order.Pay())
+	dispatcher, _ := recordingDispatcher(t)
+	uc := NewUpdateOrderUseCase(newMemoryOrderRepository(order), event.NewOrderUpdatedFactory(), dispatcher)
+
+	_, err := uc.Execute(context.Background(), UpdateOrderInputDTO{
+		ID:       "a",
+		Version:  1,
+		Currency: "USD",
+		Items:    []OrderItemInputDTO{{SKU: "SKU-2", Quantity: 1, UnitPrice: 500}},
+	})
+
+	assert.ErrorIs(t, err, entity.ErrOrderNotEditable)
+}
@@ -0,0 +1,112 @@
+// Package dataloader batches and caches lookups by key, so resolvers that
+// each need one record share a single query instead of issuing N.
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrNotFound = errors.New("dataloader: key not found")
+
+const (
+	DefaultWait     = time.Millisecond
+	DefaultMaxBatch = 100
+)
+
+// FetchFunc loads the values of keys; keys missing from the map are not
+// found.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys asked for within Wait of each other, up to
+// MaxBatch, and loads them with one Fetch call. Results are cached for the
+// loader's lifetime, so create one per request.
+type Loader[K comparable, V any] struct {
+	Fetch    FetchFunc[K, V]
+	Wait     time.Duration
+	MaxBatch int
+
+	mu      sync.Mutex
+	pending *batch[K, V]
+	results map[K]*batch[K, V]
+}
+
+func New[K comparable, V any](fetch FetchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		Fetch:    fetch,
+		Wait:     DefaultWait,
+		MaxBatch: DefaultMaxBatch,
+		results:  make(map[K]*batch[K, V]),
+	}
+}
+
+type batch[K comparable, V any] struct {
+	keys       []K
+	dispatched bool
+	done       chan struct{}
+	values     map[K]V
+	err        error
+}
+
+// Load returns the value of key, waiting for the batch it joins to be
+// fetched. A key the fetch did not return yields ErrNotFound.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	b := l.enqueue(ctx, key)
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+	if b.err != nil {
+		var zero V
+		return zero, b.err
+	}
+	value, ok := b.values[key]
+	if !ok {
+		return value, ErrNotFound
+	}
+	return value, nil
+}
+
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K) *batch[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.results[key]; ok {
+		return b
+	}
+	b := l.pending
+	if b == nil {
+		b = &batch[K, V]{done: make(chan struct{})}
+		l.pending = b
+		time.AfterFunc(l.Wait, func() { l.dispatch(ctx, b) })
+	}
+	b.keys = append(b.keys, key)
+	l.results[key] = b
+	if len(b.keys) >= l.MaxBatch {
+		l.pending = nil
+		go l.dispatch(ctx, b)
+	}
+	return b
+}
+
+// dispatch fetches b once, whichever of the timer and a full batch comes
+// first. The batch is shared by several callers, so one of them giving up
+// does not cancel the fetch for the others.
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	l.mu.Lock()
+	if b.dispatched {
+		l.mu.Unlock()
+		return
+	}
+	b.dispatched = true
+	if l.pending == b {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+
+	b.values, b.err = l.Fetch(context.WithoutCancel(ctx), b.keys)
+	close(b.done)
+}
@@ -0,0 +1,89 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (f *recordingFetch) fetch(_ context.Context, keys []string) (map[string]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	f.batches = append(f.batches, sorted)
+	values := make(map[string]int)
+	for _, key := range keys {
+		if key != "missing" {
+			values[key] = len(key)
+		}
+	}
+	return values, nil
+}
+
+func loadAll(loader *Loader[string, int], keys ...string) ([]int, []error) {
+	values := make([]int, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = loader.Load(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+	return values, errs
+}
+
+func TestGivenConcurrentLoads_WhenLoad_ThenShouldFetchThemInOneBatch(t *testing.T) {
+	fetch := &recordingFetch{}
+	loader := New(fetch.fetch)
+	loader.Wait = 50 * time.Millisecond
+
+	values, errs := loadAll(loader, "a", "bb", "a", "missing")
+
+	assert.Equal(t, []int{1, 2, 1, 0}, values)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[3], ErrNotFound)
+	require.Len(t, fetch.batches, 1)
+	assert.Equal(t, []string{"a", "bb", "missing"}, fetch.batches[0])
+
+	value, err := loader.Load(context.Background(), "bb")
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+	assert.Len(t, fetch.batches, 1)
+}
+
+func TestGivenMoreKeysThanMaxBatch_WhenLoad_ThenShouldSplitTheBatches(t *testing.T) {
+	fetch := &recordingFetch{}
+	loader := New(fetch.fetch)
+	loader.MaxBatch = 2
+
+	_, errs := loadAll(loader, "a", "b", "c")
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Len(t, fetch.batches, 2)
+}
+
+func TestGivenAFailingFetch_WhenLoad_ThenShouldReturnTheError(t *testing.T) {
+	loader := New(func(context.Context, []string) (map[string]int, error) {
+		return nil, errors.New("db down")
+	})
+
+	_, err := loader.Load(context.Background(), "a")
+	assert.EqualError(t, err, "db down")
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrSubscriberTooSlow = errors.New("subscriber fell too far behind")
+	ErrBroadcasterClosed = errors.New("broadcaster closed")
+)
+
+// DefaultSubscriptionBuffer is how many events a subscriber may fall behind.
+const DefaultSubscriptionBuffer = 64
+
+// Broadcaster is a handler that fans every event it handles out to its
+// subscribers, such as streaming API clients. Register it on a dispatcher
+// for each event name to watch. Handle never blocks on a subscriber: one
+// whose buffer is full is closed with ErrSubscriberTooSlow, so it can
+// resynchronize instead of silently missing events.
+type Broadcaster struct {
+	Buffer int
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		Buffer:      DefaultSubscriptionBuffer,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives events on C until it is closed, by Close, by the
+// broadcaster or for being too slow; Err then tells why.
+type Subscription struct {
+	C <-chan EventInterface
+
+	events      chan EventInterface
+	filter      func(EventInterface) bool
+	broadcaster *Broadcaster
+	err         error
+}
+
+// Subscribe returns a subscription to the events for which filter returns
+// true; a nil filter accepts all.
+func (b *Broadcaster) Subscribe(filter func(EventInterface) bool) *Subscription {
+	events := make(chan EventInterface, b.Buffer)
+	s := &Subscription{C: events, events: events, filter: filter, broadcaster: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.err = ErrBroadcasterClosed
+		close(events)
+		return s
+	}
+	b.subscribers[s] = struct{}{}
+	return s
+}
+
+func (b *Broadcaster) Handle(_ context.Context, event EventInterface) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subscribers {
+		if s.filter != nil && !s.filter(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			b.remove(s, ErrSubscriberTooSlow)
+		}
+	}
+	return nil
+}
+
+// Subscribers returns how many subscriptions are open.
+func (b *Broadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close ends every subscription with ErrBroadcasterClosed.
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for s := range b.subscribers {
+		b.remove(s, ErrBroadcasterClosed)
+	}
+}
+
+// remove must be called with b.mu held.
+func (b *Broadcaster) remove(s *Subscription, err error) {
+	if _, ok := b.subscribers[s]; !ok {
+		return
+	}
+	delete(b.subscribers, s)
+	s.err = err
+	close(s.events)
+}
+
+// Close unsubscribes; C is closed and Err returns nil.
+func (s *Subscription) Close() {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	s.broadcaster.remove(s, nil)
+}
+
+// Err reports why C was closed by the broadcaster. Read it once C is closed.
+func (s *Subscription) Err() error {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	return s.err
+}
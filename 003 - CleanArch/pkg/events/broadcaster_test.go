@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenSubscribers_WhenDispatch_ThenShouldDeliverMatchingEvents(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	dispatcher := NewEventDispatcher()
+	require.NoError(t, dispatcher.Register("OrderCreated", broadcaster))
+	require.NoError(t, dispatcher.Register("OrderPaid", broadcaster))
+
+	all := broadcaster.Subscribe(nil)
+	paid := broadcaster.Subscribe(func(event EventInterface) bool { return event.GetName() == "OrderPaid" })
+	defer all.Close()
+	defer paid.Close()
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), &TestEvent{Name: "OrderCreated"}))
+	require.NoError(t, dispatcher.Dispatch(context.Background(), &TestEvent{Name: "OrderPaid"}))
+
+	assert.Equal(t, "OrderCreated", (<-all.C).GetName())
+	assert.Equal(t, "OrderPaid", (<-all.C).GetName())
+	assert.Equal(t, "OrderPaid", (<-paid.C).GetName())
+	assert.Empty(t, paid.C)
+}
+
+func TestGivenASlowSubscriber_WhenItsBufferIsFull_ThenShouldCloseIt(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	broadcaster.Buffer = 1
+	slow := broadcaster.Subscribe(nil)
+
+	require.NoError(t, broadcaster.Handle(context.Background(), &TestEvent{Name: "OrderCreated"}))
+	require.NoError(t, broadcaster.Handle(context.Background(), &TestEvent{Name: "OrderPaid"}))
+
+	assert.Equal(t, "OrderCreated", (<-slow.C).GetName())
+	_, open := <-slow.C
+	assert.False(t, open)
+	assert.ErrorIs(t, slow.Err(), ErrSubscriberTooSlow)
+}
+
+func TestGivenASubscription_WhenClosed_ThenShouldStopReceiving(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	subscription := broadcaster.Subscribe(nil)
+	subscription.Close()
+	subscription.Close()
+
+	require.NoError(t, broadcaster.Handle(context.Background(), &TestEvent{Name: "OrderCreated"}))
+	_, open := <-subscription.C
+	assert.False(t, open)
+	assert.NoError(t, subscription.Err())
+
+	broadcaster.Close()
+	late := broadcaster.Subscribe(nil)
+	_, open = <-late.C
+	assert.False(t, open)
+	assert.ErrorIs(t, late.Err(), ErrBroadcasterClosed)
+}
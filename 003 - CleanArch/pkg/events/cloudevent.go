@@ -0,0 +1,91 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	CloudEventsSpecVersion = "1.0"
+	CloudEventsContentType = "application/cloudevents+json"
+)
+
+var ErrInvalidCloudEvent = errors.New("invalid cloud event")
+
+// CloudEvent is the structured-mode CloudEvents 1.0 JSON form of an event.
+// The envelope fields without a CloudEvents attribute travel as extensions,
+// whose names the spec limits to lowercase letters and digits.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	SchemaVersion   int             `json:"schemaversion"`
+	CorrelationID   string          `json:"correlationid,omitempty"`
+	CausationID     string          `json:"causationid,omitempty"`
+	TraceParent     string          `json:"traceparent,omitempty"`
+	TraceState      string          `json:"tracestate,omitempty"`
+	Data            json.RawMessage `json:"data"`
+}
+
+func NewCloudEvent(event EventInterface) (CloudEvent, error) {
+	data, err := json.Marshal(event.GetPayload())
+	if err != nil {
+		return CloudEvent{}, err
+	}
+	m := event.GetMetadata()
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              m.ID,
+		Source:          m.Source,
+		Type:            event.GetName(),
+		Subject:         m.AggregateID,
+		Time:            m.OccurredAt,
+		DataContentType: "application/json",
+		SchemaVersion:   m.SchemaVersion,
+		CorrelationID:   m.CorrelationID,
+		CausationID:     m.CausationID,
+		TraceParent:     m.TraceParent,
+		TraceState:      m.TraceState,
+		Data:            data,
+	}, nil
+}
+
+// MarshalCloudEvent encodes event, its metadata and payload as CloudEvents JSON.
+func MarshalCloudEvent(event EventInterface) ([]byte, error) {
+	ce, err := NewCloudEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ce)
+}
+
+// ParseCloudEvent decodes CloudEvents JSON and checks the required attributes.
+func ParseCloudEvent(data []byte) (CloudEvent, error) {
+	var ce CloudEvent
+	if err := json.Unmarshal(data, &ce); err != nil {
+		return CloudEvent{}, err
+	}
+	if ce.SpecVersion != CloudEventsSpecVersion || ce.ID == "" || ce.Source == "" || ce.Type == "" {
+		return CloudEvent{}, ErrInvalidCloudEvent
+	}
+	return ce, nil
+}
+
+func (ce CloudEvent) Metadata() Metadata {
+	return Metadata{
+		ID:            ce.ID,
+		Source:        ce.Source,
+		AggregateID:   ce.Subject,
+		SchemaVersion: ce.SchemaVersion,
+		OccurredAt:    ce.Time,
+		CorrelationID: ce.CorrelationID,
+		CausationID:   ce.CausationID,
+		TraceParent:   ce.TraceParent,
+		TraceState:    ce.TraceState,
+	}
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnEvent_WhenMarshalCloudEvent_ThenShouldRoundTripEnvelope(t *testing.T) {
+	metadata := NewMetadata(context.Background(), "order-1")
+	metadata.CausationID = "command-1"
+	metadata.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	event := &TestEvent{Name: "OrderCreated", Payload: map[string]string{"id": "order-1"}, Metadata: metadata}
+
+	data, err := MarshalCloudEvent(event)
+	assert.NoError(t, err)
+
+	var raw map[string]any
+	assert.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "1.0", raw["specversion"])
+	assert.Equal(t, "OrderCreated", raw["type"])
+	assert.Equal(t, "order-1", raw["subject"])
+	assert.Equal(t, metadata.ID, raw["id"])
+	assert.Equal(t, map[string]any{"id": "order-1"}, raw["data"])
+
+	ce, err := ParseCloudEvent(data)
+	assert.NoError(t, err)
+	assert.Equal(t, metadata.ID, ce.Metadata().ID)
+	assert.Equal(t, metadata.CorrelationID, ce.Metadata().CorrelationID)
+	assert.Equal(t, "command-1", ce.Metadata().CausationID)
+	assert.Equal(t, metadata.TraceParent, ce.Metadata().TraceParent)
+	assert.Equal(t, 1, ce.Metadata().SchemaVersion)
+	assert.True(t, metadata.OccurredAt.Equal(ce.Metadata().OccurredAt))
+}
+
+func TestGivenMissingAttributes_WhenParseCloudEvent_ThenShouldReturnError(t *testing.T) {
+	_, err := ParseCloudEvent([]byte(`{"specversion":"1.0","type":"OrderCreated","data":{}}`))
+	assert.ErrorIs(t, err, ErrInvalidCloudEvent)
+}
+
+func TestGivenNewMetadata_WhenCreated_ThenShouldStampOccurredAt(t *testing.T) {
+	before := time.Now().UTC()
+	metadata := NewMetadata(context.Background(), "order-1")
+	event := &TestEvent{Name: "OrderCreated", Metadata: metadata}
+
+	assert.NotEmpty(t, metadata.ID)
+	assert.Equal(t, metadata.ID, metadata.CorrelationID)
+	assert.False(t, event.GetDateTime().Before(before))
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, metadata.OccurredAt, event.GetDateTime())
+}
+
+func TestGivenARequestAndTheEventsItCauses_WhenNewMetadata_ThenShouldChainThem(t *testing.T) {
+	ctx := WithCorrelationID(context.Background(), "request-1")
+
+	created := NewMetadata(ctx, "order-1")
+	assert.Equal(t, "request-1", created.CorrelationID)
+	assert.Equal(t, "request-1", created.CausationID)
+
+	// an event raised by a handler of created
+	followUp := NewMetadata(WithCause(context.Background(), created), "order-1")
+	assert.Equal(t, "request-1", followUp.CorrelationID)
+	assert.Equal(t, created.ID, followUp.CausationID)
+}
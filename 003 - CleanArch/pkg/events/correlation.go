@@ -0,0 +1,41 @@
+package events
+
+import "context"
+
+type correlationKey struct{}
+
+type causationKey struct{}
+
+// WithCorrelationID returns ctx carrying the ID of the request that starts a
+// chain of events, such as an HTTP X-Request-Id. The request is also the
+// cause of the events it raises directly.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// WithCause returns ctx for handling the event described by metadata: the
+// events raised while handling it join its chain and name it as their cause.
+func WithCause(ctx context.Context, metadata Metadata) context.Context {
+	ctx = WithCorrelationID(ctx, metadata.CorrelationID)
+	if metadata.ID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, causationKey{}, metadata.ID)
+}
+
+// CorrelationID is the correlation ID ctx carries, if any.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// CausationID is the ID of the event or request ctx is handling, if any.
+func CausationID(ctx context.Context) string {
+	if id, ok := ctx.Value(causationKey{}).(string); ok {
+		return id
+	}
+	return CorrelationID(ctx)
+}
@@ -1,38 +1,183 @@
 package events
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"reflect"
 	"sync"
 )
 
-var ErrHandlerAlreadyRegistered = errors.New("handler already registered")
+var (
+	ErrHandlerAlreadyRegistered = errors.New("handler already registered")
+	ErrDispatcherClosed         = errors.New("dispatcher closed")
+)
 
+// EventDispatcher is safe for concurrent use. In the default synchronous
+// mode Dispatch runs every handler and returns their errors joined. An async
+// dispatcher hands the calls to a fixed pool of workers and reports handler
+// errors to OnError instead. Handler panics are recovered and reported as
+// errors in both modes.
 type EventDispatcher struct {
-	handlers map[string][]EventHandlerInterface
+	mu          sync.RWMutex
+	handlers    map[string][]EventHandlerInterface
+	middlewares []Middleware
+
+	// OnError receives handler errors in async mode. It defaults to logging.
+	OnError func(event EventInterface, err error)
+
+	// jobsMu guards sending on jobs against Close closing it: senders hold
+	// the read lock, Close the write lock.
+	jobsMu    sync.RWMutex
+	jobs      chan dispatchJob
+	workers   sync.WaitGroup
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+type dispatchJob struct {
+	ctx     context.Context
+	handler EventHandlerInterface
+	event   EventInterface
 }
 
 func NewEventDispatcher() *EventDispatcher {
 	return &EventDispatcher{
 		handlers: make(map[string][]EventHandlerInterface),
+		OnError:  logDispatchError,
+		closed:   make(chan struct{}),
+	}
+}
+
+// NewAsyncEventDispatcher starts workers goroutines fed by a queue of
+// queueSize handler calls. Dispatch blocks while the queue is full. Call
+// Close to drain the queue and stop the workers.
+func NewAsyncEventDispatcher(workers, queueSize int) *EventDispatcher {
+	if workers <= 0 {
+		workers = 1
+	}
+	ed := NewEventDispatcher()
+	ed.jobs = make(chan dispatchJob, queueSize)
+	for i := 0; i < workers; i++ {
+		ed.workers.Add(1)
+		go func() {
+			defer ed.workers.Done()
+			for job := range ed.jobs {
+				if err := safeHandle(job.ctx, job.handler, job.event); err != nil {
+					ed.OnError(job.event, err)
+				}
+			}
+		}()
+	}
+	return ed
+}
+
+// Dispatch runs the handlers with ctx, marking event as the cause of the
+// events they raise. Async handlers outlive the caller, so they get ctx's
+// values but not its cancellation.
+func (ed *EventDispatcher) Dispatch(ctx context.Context, event EventInterface) error {
+	select {
+	case <-ed.closed:
+		return ErrDispatcherClosed
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx = WithCause(ctx, event.GetMetadata())
+	ed.mu.RLock()
+	handlers := make([]EventHandlerInterface, 0, len(ed.handlers[event.GetName()]))
+	for _, handler := range ed.handlers[event.GetName()] {
+		handlers = append(handlers, Chain(handler, ed.middlewares...))
+	}
+	ed.mu.RUnlock()
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	if ed.jobs != nil {
+		return ed.enqueue(ctx, event, handlers)
+	}
+
+	errs := make([]error, len(handlers))
+	wg := &sync.WaitGroup{}
+	for i, handler := range handlers {
+		wg.Add(1)
+		go func(i int, handler EventHandlerInterface) {
+			defer wg.Done()
+			errs[i] = safeHandle(ctx, handler, event)
+		}(i, handler)
 	}
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
-func (ev *EventDispatcher) Dispatch(event EventInterface) error {
-	if handlers, ok := ev.handlers[event.GetName()]; ok {
-		wg := &sync.WaitGroup{}
-		for _, handler := range handlers {
-			wg.Add(1)
-			go handler.Handle(event, wg)
+func (ed *EventDispatcher) enqueue(ctx context.Context, event EventInterface, handlers []EventHandlerInterface) error {
+	ed.jobsMu.RLock()
+	defer ed.jobsMu.RUnlock()
+	jobCtx := context.WithoutCancel(ctx)
+	for _, handler := range handlers {
+		// checked first, as select picks at random among ready cases
+		select {
+		case <-ed.closed:
+			return ErrDispatcherClosed
+		default:
+		}
+		select {
+		case <-ed.closed:
+			return ErrDispatcherClosed
+		case <-ctx.Done():
+			// the caller gave up while the queue was full
+			return ctx.Err()
+		case ed.jobs <- dispatchJob{ctx: jobCtx, handler: handler, event: event}:
 		}
-		wg.Wait()
 	}
 	return nil
 }
 
+// Close stops accepting events and, in async mode, waits for queued handler
+// calls to finish. Closing the closed channel first wakes the Dispatch calls
+// blocked on a full queue, so they release the read lock Close waits for.
+func (ed *EventDispatcher) Close() {
+	ed.closeOnce.Do(func() {
+		close(ed.closed)
+		if ed.jobs != nil {
+			ed.jobsMu.Lock()
+			close(ed.jobs)
+			ed.jobsMu.Unlock()
+			ed.workers.Wait()
+		}
+	})
+}
+
+// Use appends middlewares applied to every handler call, the first one
+// outermost. They apply from the next Dispatch on.
+func (ed *EventDispatcher) Use(middlewares ...Middleware) {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+	ed.middlewares = append(ed.middlewares, middlewares...)
+}
+
+func safeHandle(ctx context.Context, handler EventHandlerInterface, event EventInterface) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic on %s: %v", event.GetName(), r)
+		}
+	}()
+	return handler.Handle(ctx, event)
+}
+
+func logDispatchError(event EventInterface, err error) {
+	log.Printf("events: %s %s: %v", event.GetName(), event.GetMetadata().ID, err)
+}
+
 func (ed *EventDispatcher) Register(eventName string, handler EventHandlerInterface) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
 	if _, ok := ed.handlers[eventName]; ok {
 		for _, h := range ed.handlers[eventName] {
-			if h == handler {
+			if sameHandler(h, handler) {
 				return ErrHandlerAlreadyRegistered
 			}
 		}
@@ -42,9 +187,11 @@ func (ed *EventDispatcher) Register(eventName string, handler EventHandlerInterf
 }
 
 func (ed *EventDispatcher) Has(eventName string, handler EventHandlerInterface) bool {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
 	if _, ok := ed.handlers[eventName]; ok {
 		for _, h := range ed.handlers[eventName] {
-			if h == handler {
+			if sameHandler(h, handler) {
 				return true
 			}
 		}
@@ -53,9 +200,11 @@ func (ed *EventDispatcher) Has(eventName string, handler EventHandlerInterface)
 }
 
 func (ed *EventDispatcher) Remove(eventName string, handler EventHandlerInterface) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
 	if _, ok := ed.handlers[eventName]; ok {
 		for i, h := range ed.handlers[eventName] {
-			if h == handler {
+			if sameHandler(h, handler) {
 				ed.handlers[eventName] = append(ed.handlers[eventName][:i], ed.handlers[eventName][i+1:]...)
 				return nil
 			}
@@ -65,5 +214,16 @@ func (ed *EventDispatcher) Remove(eventName string, handler EventHandlerInterfac
 }
 
 func (ed *EventDispatcher) Clear() {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
 	ed.handlers = make(map[string][]EventHandlerInterface)
 }
+
+// sameHandler compares handlers without panicking on uncomparable ones, such
+// as a HandlerFunc, which never match.
+func sameHandler(a, b EventHandlerInterface) bool {
+	if !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return false
+	}
+	return a == b
+}
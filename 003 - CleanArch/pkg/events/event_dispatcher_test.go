@@ -1,7 +1,10 @@
 package events
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,8 +14,9 @@ import (
 )
 
 type TestEvent struct {
-	Name    string
-	Payload interface{}
+	Name     string
+	Payload  interface{}
+	Metadata Metadata
 }
 
 func (e *TestEvent) GetName() string {
@@ -24,7 +28,15 @@ func (e *TestEvent) GetPayload() interface{} {
 }
 
 func (e *TestEvent) GetDateTime() time.Time {
-	return time.Now()
+	return e.Metadata.OccurredAt
+}
+
+func (e *TestEvent) GetMetadata() Metadata {
+	return e.Metadata
+}
+
+func (e *TestEvent) SetMetadata(metadata Metadata) {
+	e.Metadata = metadata
 }
 
 func (e *TestEvent) SetPayload(payload interface{}) {
@@ -35,7 +47,8 @@ type TestEventHandler struct {
 	ID int
 }
 
-func (h *TestEventHandler) Handle(event EventInterface, wg *sync.WaitGroup) {
+func (h *TestEventHandler) Handle(ctx context.Context, event EventInterface) error {
+	return nil
 }
 
 type EventDispatcherTestSuite struct {
@@ -150,28 +163,142 @@ type MockHandler struct {
 	mock.Mock
 }
 
-func (m *MockHandler) Handle(event EventInterface, wg *sync.WaitGroup) {
-	m.Called(event)
-	wg.Done()
+func (m *MockHandler) Handle(ctx context.Context, event EventInterface) error {
+	return m.Called(event).Error(0)
 }
 
 func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch() {
 	eh := &MockHandler{}
-	eh.On("Handle", &suite.event)
+	eh.On("Handle", &suite.event).Return(nil)
 
 	eh2 := &MockHandler{}
-	eh2.On("Handle", &suite.event)
+	eh2.On("Handle", &suite.event).Return(nil)
 
 	suite.eventDispatcher.Register(suite.event.GetName(), eh)
 	suite.eventDispatcher.Register(suite.event.GetName(), eh2)
 
-	suite.eventDispatcher.Dispatch(&suite.event)
+	suite.eventDispatcher.Dispatch(context.Background(), &suite.event)
 	eh.AssertExpectations(suite.T())
 	eh2.AssertExpectations(suite.T())
 	eh.AssertNumberOfCalls(suite.T(), "Handle", 1)
 	eh2.AssertNumberOfCalls(suite.T(), "Handle", 1)
 }
 
+func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch_WithFailingHandlers() {
+	errFirst := errors.New("first failed")
+	eh := &MockHandler{}
+	eh.On("Handle", &suite.event).Return(errFirst)
+	eh2 := &MockHandler{}
+	eh2.On("Handle", &suite.event).Return(nil)
+	suite.eventDispatcher.Register(suite.event.GetName(), eh)
+	suite.eventDispatcher.Register(suite.event.GetName(), eh2)
+	suite.eventDispatcher.Register(suite.event.GetName(), panicHandler{})
+
+	err := suite.eventDispatcher.Dispatch(context.Background(), &suite.event)
+	suite.ErrorIs(err, errFirst)
+	suite.ErrorContains(err, "handler panic on test: boom")
+	eh2.AssertNumberOfCalls(suite.T(), "Handle", 1)
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch_Async() {
+	dispatcher := NewAsyncEventDispatcher(2, 1)
+	var mu sync.Mutex
+	var failures []error
+	dispatcher.OnError = func(event EventInterface, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, err)
+	}
+	counter := &countingHandler{}
+	dispatcher.Register(suite.event.GetName(), counter)
+	dispatcher.Register(suite.event.GetName(), panicHandler{})
+
+	for i := 0; i < 10; i++ {
+		suite.NoError(dispatcher.Dispatch(context.Background(), &suite.event))
+	}
+	dispatcher.Close()
+
+	suite.Equal(int64(10), counter.calls.Load())
+	suite.Len(failures, 10)
+	suite.ErrorIs(dispatcher.Dispatch(context.Background(), &suite.event), ErrDispatcherClosed)
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatch_CloseWhileDispatchersWaitOnAFullQueue() {
+	dispatcher := NewAsyncEventDispatcher(1, 1)
+	release := make(chan struct{})
+	dispatcher.Register(suite.event.GetName(), HandlerFunc(func(ctx context.Context, event EventInterface) error {
+		<-release
+		return nil
+	}))
+
+	errs := make(chan error, 20)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- dispatcher.Dispatch(context.Background(), &suite.event)
+		}()
+	}
+	closed := make(chan struct{})
+	go func() {
+		dispatcher.Close()
+		close(closed)
+	}()
+	wg.Wait()
+	close(release)
+	<-closed
+
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			suite.ErrorIs(err, ErrDispatcherClosed)
+		}
+	}
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatch_ConcurrentRegisterAndDispatch() {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			suite.eventDispatcher.Register(suite.event.GetName(), &countingHandler{})
+		}()
+		go func() {
+			defer wg.Done()
+			suite.eventDispatcher.Dispatch(context.Background(), &suite.event)
+		}()
+	}
+	wg.Wait()
+	suite.Equal(50, len(suite.eventDispatcher.handlers[suite.event.GetName()]))
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch_WithCancelledContext() {
+	eh := &MockHandler{}
+	suite.eventDispatcher.Register(suite.event.GetName(), eh)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.ErrorIs(suite.eventDispatcher.Dispatch(ctx, &suite.event), context.Canceled)
+	eh.AssertNotCalled(suite.T(), "Handle", &suite.event)
+}
+
+type panicHandler struct{}
+
+func (panicHandler) Handle(ctx context.Context, event EventInterface) error {
+	panic("boom")
+}
+
+type countingHandler struct {
+	calls atomic.Int64
+}
+
+func (h *countingHandler) Handle(ctx context.Context, event EventInterface) error {
+	h.calls.Add(1)
+	return nil
+}
+
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(EventDispatcherTestSuite))
 }
@@ -1,25 +1,53 @@
 package events
 
 import (
-	"sync"
+	"context"
 	"time"
 )
 
 type EventInterface interface {
 	GetName() string
+	// GetDateTime returns when the event occurred, taken from its metadata.
 	GetDateTime() time.Time
 	GetPayload() interface{}
 	SetPayload(payload interface{})
+	GetMetadata() Metadata
+	SetMetadata(metadata Metadata)
 }
 
+// EventFactory builds a new event instance, so each dispatch gets its own
+// instead of concurrent requests sharing and mutating one.
+type EventFactory func() EventInterface
+
+// EventHandlerInterface handles one event. Handlers should stop when ctx is
+// done.
 type EventHandlerInterface interface {
-	Handle(event EventInterface, wg *sync.WaitGroup)
+	Handle(ctx context.Context, event EventInterface) error
 }
 
 type EventDispatcherInterface interface {
 	Register(eventName string, handler EventHandlerInterface) error
-	Dispatch(event EventInterface) error
+	Dispatch(ctx context.Context, event EventInterface) error
 	Remove(eventName string, handler EventHandlerInterface) error
 	Has(eventName string, handler EventHandlerInterface) bool
 	Clear()
 }
+
+// Message is an event serialized for a message broker. Key groups messages
+// that must stay ordered, such as events of the same aggregate.
+type Message struct {
+	ID          string
+	Name        string
+	Key         string
+	ContentType string
+	Payload     []byte
+	Timestamp   time.Time
+	Headers     map[string]string
+}
+
+// Publisher is the port to a message broker. Publish returns nil only once
+// the broker has accepted the message.
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+	Close() error
+}
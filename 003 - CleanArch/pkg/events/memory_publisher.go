@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrPublisherClosed = errors.New("publisher closed")
+
+// InMemoryPublisher keeps published messages in memory and hands them to its
+// subscribers synchronously. It lets the event pipeline run without a broker.
+type InMemoryPublisher struct {
+	mu          sync.Mutex
+	messages    []Message
+	subscribers []func(Message)
+	closed      bool
+}
+
+func NewInMemoryPublisher() *InMemoryPublisher {
+	return &InMemoryPublisher{}
+}
+
+func (p *InMemoryPublisher) Publish(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPublisherClosed
+	}
+	p.messages = append(p.messages, message)
+	subscribers := append([]func(Message){}, p.subscribers...)
+	p.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(message)
+	}
+	return nil
+}
+
+// Subscribe registers fn to receive every message published afterwards.
+func (p *InMemoryPublisher) Subscribe(fn func(Message)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscribers = append(p.subscribers, fn)
+}
+
+// Messages returns a copy of everything published so far.
+func (p *InMemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
+
+func (p *InMemoryPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
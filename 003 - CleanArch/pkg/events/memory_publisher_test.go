@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenASubscriber_WhenPublish_ThenShouldRecordAndDeliverMessage(t *testing.T) {
+	publisher := NewInMemoryPublisher()
+	var received []Message
+	publisher.Subscribe(func(m Message) { received = append(received, m) })
+
+	message := Message{ID: "1", Name: "OrderCreated", Key: "a", Payload: []byte(`{}`)}
+	assert.NoError(t, publisher.Publish(context.Background(), message))
+
+	assert.Equal(t, []Message{message}, publisher.Messages())
+	assert.Equal(t, []Message{message}, received)
+}
+
+func TestGivenAClosedPublisher_WhenPublish_ThenShouldReturnError(t *testing.T) {
+	publisher := NewInMemoryPublisher()
+	assert.NoError(t, publisher.Close())
+
+	err := publisher.Publish(context.Background(), Message{ID: "1"})
+	assert.ErrorIs(t, err, ErrPublisherClosed)
+	assert.Empty(t, publisher.Messages())
+}
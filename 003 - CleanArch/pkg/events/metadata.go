@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSource is the CloudEvents source of events raised by this service.
+const DefaultSource = "/cleanarch/ordersystem"
+
+// Metadata is the envelope every event carries next to its payload.
+// CorrelationID ties together all events caused by one request, CausationID
+// is the ID of the event or command that directly caused this one, and
+// TraceParent/TraceState hold the W3C trace context.
+type Metadata struct {
+	ID            string
+	Source        string
+	AggregateID   string
+	SchemaVersion int
+	OccurredAt    time.Time
+	CorrelationID string
+	CausationID   string
+	TraceParent   string
+	TraceState    string
+}
+
+// NewMetadata stamps a new event for aggregateID with a fresh ID, the current
+// time and schema version 1. It joins the chain of the request or event ctx
+// carries; without one the event starts its own chain.
+func NewMetadata(ctx context.Context, aggregateID string) Metadata {
+	id := uuid.NewString()
+	correlationID := CorrelationID(ctx)
+	if correlationID == "" {
+		correlationID = id
+	}
+	return Metadata{
+		ID:            id,
+		Source:        DefaultSource,
+		AggregateID:   aggregateID,
+		SchemaVersion: 1,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+		CausationID:   CausationID(ctx),
+	}
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+var ErrHandlerTimeout = errors.New("event handler timed out")
+
+// Middleware wraps a handler with behaviour shared by every handler, such as
+// logging or retries.
+type Middleware func(next EventHandlerInterface) EventHandlerInterface
+
+// HandlerFunc adapts a function to EventHandlerInterface. Functions can't be
+// compared, so a registered HandlerFunc can't be found by Has or Remove.
+type HandlerFunc func(ctx context.Context, event EventInterface) error
+
+func (f HandlerFunc) Handle(ctx context.Context, event EventInterface) error {
+	return f(ctx, event)
+}
+
+// Chain wraps handler so that the first middleware runs outermost.
+func Chain(handler EventHandlerInterface, middlewares ...Middleware) EventHandlerInterface {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Logging logs every handled event with its duration and outcome. A nil
+// logger uses the standard logger.
+func Logging(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next EventHandlerInterface) EventHandlerInterface {
+		return HandlerFunc(func(ctx context.Context, event EventInterface) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+			if err != nil {
+				logger.Printf("events: %s %s failed after %s: %v", event.GetName(), event.GetMetadata().ID, time.Since(start), err)
+			} else {
+				logger.Printf("events: %s %s handled in %s", event.GetName(), event.GetMetadata().ID, time.Since(start))
+			}
+			return err
+		})
+	}
+}
+
+// MetricsRecorder receives one observation per handler call.
+type MetricsRecorder interface {
+	ObserveHandler(eventName string, duration time.Duration, err error)
+}
+
+// MetricsRecorderFunc adapts a function to MetricsRecorder.
+type MetricsRecorderFunc func(eventName string, duration time.Duration, err error)
+
+func (f MetricsRecorderFunc) ObserveHandler(eventName string, duration time.Duration, err error) {
+	f(eventName, duration, err)
+}
+
+func Metrics(recorder MetricsRecorder) Middleware {
+	return func(next EventHandlerInterface) EventHandlerInterface {
+		return HandlerFunc(func(ctx context.Context, event EventInterface) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+			recorder.ObserveHandler(event.GetName(), time.Since(start), err)
+			return err
+		})
+	}
+}
+
+// Retry calls the handler up to attempts times, doubling the wait after each
+// failure starting at backoff. It returns the last error, or ctx's error if
+// ctx is done while waiting.
+func Retry(attempts int, backoff time.Duration) Middleware {
+	return func(next EventHandlerInterface) EventHandlerInterface {
+		return HandlerFunc(func(ctx context.Context, event EventInterface) error {
+			var err error
+			delay := backoff
+			for attempt := 1; ; attempt++ {
+				if err = next.Handle(ctx, event); err == nil || attempt >= attempts {
+					return err
+				}
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return errors.Join(err, ctx.Err())
+				case <-timer.C:
+				}
+				delay *= 2
+			}
+		})
+	}
+}
+
+// Timeout cancels the handler's context after d and fails the call with
+// ErrHandlerTimeout. A handler that ignores its context keeps running in the
+// background and its result is discarded.
+func Timeout(d time.Duration) Middleware {
+	return func(next EventHandlerInterface) EventHandlerInterface {
+		return HandlerFunc(func(ctx context.Context, event EventInterface) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			done := make(chan error, 1)
+			go func() {
+				done <- safeHandle(ctx, next, event)
+			}()
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return fmt.Errorf("%w: %s after %s", ErrHandlerTimeout, event.GetName(), d)
+				}
+				return ctx.Err()
+			}
+		})
+	}
+}
+
+// Recover turns a handler panic into an error. The dispatcher already does
+// this around the whole chain; use Recover to let inner middleware, such as
+// Retry, see the panic as a failure.
+func Recover() Middleware {
+	return func(next EventHandlerInterface) EventHandlerInterface {
+		return HandlerFunc(func(ctx context.Context, event EventInterface) error {
+			return safeHandle(ctx, next, event)
+		})
+	}
+}
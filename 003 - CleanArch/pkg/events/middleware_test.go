@@ -0,0 +1,105 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenMiddlewares_WhenDispatch_ThenShouldWrapHandlersInOrder(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next EventHandlerInterface) EventHandlerInterface {
+			return HandlerFunc(func(ctx context.Context, event EventInterface) error {
+				calls = append(calls, name)
+				return next.Handle(ctx, event)
+			})
+		}
+	}
+	dispatcher.Use(trace("outer"), trace("inner"))
+	assert.NoError(t, dispatcher.Register("test", HandlerFunc(func(ctx context.Context, event EventInterface) error {
+		calls = append(calls, "handler")
+		return nil
+	})))
+
+	assert.NoError(t, dispatcher.Dispatch(context.Background(), &TestEvent{Name: "test"}))
+	assert.Equal(t, []string{"outer", "inner", "handler"}, calls)
+}
+
+func TestGivenAFlakyHandler_WhenRetried_ThenShouldSucceed(t *testing.T) {
+	attempts := 0
+	handler := Chain(HandlerFunc(func(ctx context.Context, event EventInterface) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}), Retry(3, time.Millisecond))
+
+	assert.NoError(t, handler.Handle(context.Background(), &TestEvent{Name: "test"}))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestGivenAPanickingHandler_WhenRecoveredInsideRetry_ThenShouldRetryAndFail(t *testing.T) {
+	attempts := 0
+	handler := Chain(HandlerFunc(func(ctx context.Context, event EventInterface) error {
+		attempts++
+		panic("boom")
+	}), Retry(2, time.Millisecond), Recover())
+
+	err := handler.Handle(context.Background(), &TestEvent{Name: "test"})
+	assert.ErrorContains(t, err, "handler panic on test: boom")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestGivenASlowHandler_WhenTimeoutExpires_ThenShouldCancelItsContext(t *testing.T) {
+	cancelled := make(chan struct{})
+	handler := Chain(HandlerFunc(func(ctx context.Context, event EventInterface) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}), Timeout(10*time.Millisecond))
+
+	assert.ErrorIs(t, handler.Handle(context.Background(), &TestEvent{Name: "test"}), ErrHandlerTimeout)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+}
+
+func TestGivenACancelledContext_WhenRetrying_ThenShouldStopWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	handler := Chain(HandlerFunc(func(ctx context.Context, event EventInterface) error {
+		attempts++
+		cancel()
+		return errors.New("temporary")
+	}), Retry(5, time.Hour))
+
+	err := handler.Handle(ctx, &TestEvent{Name: "test"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestGivenLoggingAndMetrics_WhenHandlerFails_ThenShouldRecordTheFailure(t *testing.T) {
+	var logs bytes.Buffer
+	var observed []error
+	failure := errors.New("failed")
+	handler := Chain(HandlerFunc(func(ctx context.Context, event EventInterface) error {
+		return failure
+	}), Logging(log.New(&logs, "", 0)), Metrics(MetricsRecorderFunc(func(eventName string, duration time.Duration, err error) {
+		assert.Equal(t, "test", eventName)
+		observed = append(observed, err)
+	})))
+
+	assert.ErrorIs(t, handler.Handle(context.Background(), &TestEvent{Name: "test", Metadata: Metadata{ID: "1"}}), failure)
+	assert.Equal(t, []error{failure}, observed)
+	assert.Contains(t, logs.String(), "events: test 1 failed after")
+}
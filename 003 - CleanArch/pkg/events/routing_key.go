@@ -0,0 +1,25 @@
+package events
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RoutingKey turns an event name into a dotted routing key, e.g.
+// "OrderCreated" becomes "order.created" and "OrderStatusChanged" becomes
+// "order.status.changed".
+func RoutingKey(eventName string) string {
+	var b strings.Builder
+	runes := []rune(eventName)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// start a new word unless this continues an acronym ("HTTPRequest")
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('.')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
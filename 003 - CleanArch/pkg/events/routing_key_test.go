@@ -0,0 +1,15 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenEventNames_WhenRoutingKey_ThenShouldReturnDottedLowercase(t *testing.T) {
+	assert.Equal(t, "order.created", RoutingKey("OrderCreated"))
+	assert.Equal(t, "order.status.changed", RoutingKey("OrderStatusChanged"))
+	assert.Equal(t, "order", RoutingKey("Order"))
+	assert.Equal(t, "http.request.failed", RoutingKey("HTTPRequestFailed"))
+	assert.Equal(t, "order.created", RoutingKey("order.created"))
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrPayloadType = errors.New("unexpected event payload type")
+
+// TypedHandlerFunc handles an event whose payload has already been converted
+// to T.
+type TypedHandlerFunc[T any] func(ctx context.Context, event EventInterface, payload T) error
+
+type typedHandler[T any] struct {
+	handle TypedHandlerFunc[T]
+}
+
+// Typed adapts handle to EventHandlerInterface. Each call returns a distinct
+// handler, so keep the result to Remove it later.
+func Typed[T any](handle TypedHandlerFunc[T]) EventHandlerInterface {
+	return &typedHandler[T]{handle: handle}
+}
+
+func (h *typedHandler[T]) Handle(ctx context.Context, event EventInterface) error {
+	payload, err := PayloadAs[T](event)
+	if err != nil {
+		return err
+	}
+	return h.handle(ctx, event, payload)
+}
+
+// Register subscribes a typed handler to eventName and returns the handler
+// that was registered.
+func Register[T any](dispatcher EventDispatcherInterface, eventName string, handle TypedHandlerFunc[T]) (EventHandlerInterface, error) {
+	handler := Typed(handle)
+	if err := dispatcher.Register(eventName, handler); err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
+
+// PayloadAs returns the payload of event as T. A T or *T payload is used as
+// is; raw JSON, such as the data of a parsed CloudEvent, is decoded into T.
+func PayloadAs[T any](event EventInterface) (T, error) {
+	var payload T
+	switch p := event.GetPayload().(type) {
+	case T:
+		return p, nil
+	case *T:
+		if p != nil {
+			return *p, nil
+		}
+	case json.RawMessage:
+		if err := json.Unmarshal(p, &payload); err != nil {
+			return payload, fmt.Errorf("%w: %s: %v", ErrPayloadType, event.GetName(), err)
+		}
+		return payload, nil
+	case []byte:
+		if err := json.Unmarshal(p, &payload); err != nil {
+			return payload, fmt.Errorf("%w: %s: %v", ErrPayloadType, event.GetName(), err)
+		}
+		return payload, nil
+	}
+	return payload, fmt.Errorf("%w: %s carries %T, want %T", ErrPayloadType, event.GetName(), event.GetPayload(), payload)
+}
+
+// LegacyEventHandler is the handler signature used before Handle took a
+// context and returned an error. The handler calls wg.Done when it finishes.
+type LegacyEventHandler interface {
+	Handle(event EventInterface, wg *sync.WaitGroup)
+}
+
+type legacyHandler struct {
+	handler LegacyEventHandler
+}
+
+// AdaptLegacy lets a LegacyEventHandler be registered on the dispatcher.
+// Adapting the same handler twice yields equal values, so Has and Remove work
+// with a fresh AdaptLegacy call.
+func AdaptLegacy(handler LegacyEventHandler) EventHandlerInterface {
+	return legacyHandler{handler: handler}
+}
+
+func (h legacyHandler) Handle(ctx context.Context, event EventInterface) error {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	h.handler.Handle(event, wg)
+	wg.Wait()
+	return nil
+}
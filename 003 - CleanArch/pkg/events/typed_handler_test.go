@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPayload struct {
+	ID string `json:"id"`
+}
+
+func TestGivenATypedHandler_WhenDispatch_ThenShouldReceiveTypedPayload(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var received []testPayload
+	handler, err := Register(dispatcher, "test", func(ctx context.Context, event EventInterface, payload testPayload) error {
+		received = append(received, payload)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, dispatcher.Has("test", handler))
+
+	assert.NoError(t, dispatcher.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: testPayload{ID: "a"}}))
+	assert.NoError(t, dispatcher.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: &testPayload{ID: "b"}}))
+	assert.NoError(t, dispatcher.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: json.RawMessage(`{"id":"c"}`)}))
+
+	assert.Equal(t, []testPayload{{ID: "a"}, {ID: "b"}, {ID: "c"}}, received)
+}
+
+func TestGivenATypedHandler_WhenPayloadHasAnotherType_ThenShouldReturnError(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	called := false
+	_, err := Register(dispatcher, "test", func(ctx context.Context, event EventInterface, payload testPayload) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = dispatcher.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: "a"})
+	assert.ErrorIs(t, err, ErrPayloadType)
+	assert.False(t, called)
+}
+
+type legacyTestHandler struct {
+	calls int
+}
+
+func (h *legacyTestHandler) Handle(event EventInterface, wg *sync.WaitGroup) {
+	h.calls++
+	wg.Done()
+}
+
+func TestGivenALegacyHandler_WhenAdapted_ThenShouldBeDispatchedAndRemovable(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	legacy := &legacyTestHandler{}
+	assert.NoError(t, dispatcher.Register("test", AdaptLegacy(legacy)))
+	assert.ErrorIs(t, dispatcher.Register("test", AdaptLegacy(legacy)), ErrHandlerAlreadyRegistered)
+
+	assert.NoError(t, dispatcher.Dispatch(context.Background(), &TestEvent{Name: "test"}))
+	assert.Equal(t, 1, legacy.calls)
+
+	assert.NoError(t, dispatcher.Remove("test", AdaptLegacy(legacy)))
+	assert.False(t, dispatcher.Has("test", AdaptLegacy(legacy)))
+}